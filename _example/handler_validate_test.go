@@ -14,6 +14,15 @@ func TestUserHandlerWithValidate(t *testing.T) {
 		ExcludeHeaders: []string{
 			"Accept-Encoding",
 		},
+		SecuritySchemes: []httpdoc.SecurityScheme{
+			{
+				Name:        "token",
+				Type:        httpdoc.SecurityAPIKey,
+				In:          httpdoc.InQuery,
+				ParamName:   "token",
+				Description: "Token issued for each client",
+			},
+		},
 	}
 	defer func() {
 		if err := document.Generate("doc/validate.md"); err != nil {
@@ -23,7 +32,11 @@ func TestUserHandlerWithValidate(t *testing.T) {
 
 	mux := http.NewServeMux()
 	mux.Handle("/v1/user", httpdoc.Record(&userHandler{}, document, &httpdoc.RecordOption{
+		Summary:     "Create user",
 		Description: "Create a new user",
+		Tags:        []string{"user"},
+		OperationID: "createUser",
+		Since:       "v1.0.0",
 		ExcludeHeaders: []string{
 			"User-Agent",
 			"Content-Length",
@@ -0,0 +1,105 @@
+package httpdoc
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Title returns the heading of the entry in documentation, e.g., `[200] GET /v1/user`.
+func (e Entry) Title() string {
+	return fmt.Sprintf("[%d] %s", e.ResponseStatusCode, e.endpoint())
+}
+
+// endpoint returns method, path and operation of the entry, e.g., `POST /graphql query GetUser`.
+// Path template is used instead of path if it's resolved.
+func (e Entry) endpoint() string {
+	if e.Operation == "" {
+		return e.Method + " " + e.path()
+	}
+	return e.Method + " " + e.path() + " " + e.Operation
+}
+
+// slugify converts the heading to anchor in the same way as GitHub and GitLab: it's lower-cased,
+// characters other than letters, numbers, spaces, hyphens and underscores are removed and spaces
+// are replaced with hyphens.
+func slugify(heading string) string {
+	var buf []rune
+	for _, r := range strings.ToLower(strings.TrimSpace(heading)) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsNumber(r), unicode.IsMark(r), r == '_', r == '-':
+			buf = append(buf, r)
+		case r == ' ':
+			buf = append(buf, '-')
+		}
+	}
+	return string(buf)
+}
+
+// setAnchors sets anchors of entries written in a file. Like GitHub and GitLab, if the anchor is
+// already used, `-1`, `-2` and so on are appended.
+func setAnchors(entries []Entry) {
+	used := make(map[string]bool)
+	for i := range entries {
+		slug := slugify(entries[i].Title())
+		anchor := slug
+		for n := 1; used[anchor]; n++ {
+			anchor = fmt.Sprintf("%s-%d", slug, n)
+		}
+		used[anchor] = true
+		entries[i].Anchor = anchor
+	}
+}
+
+// tocNode is a node of table of contents which is grouped by path segments. Nodes which have only
+// one child and no entries are merged into the child (e.g., `/v1/user`).
+type tocNode struct {
+	Path     string
+	Indent   string
+	Entries  []Entry
+	Children []*tocNode
+
+	segment string
+}
+
+// tocTree groups entries by path segments. Children and entries are ordered by first appearance.
+func tocTree(entries []Entry) []*tocNode {
+	root := &tocNode{}
+	for _, e := range entries {
+		node := root
+		for _, segment := range strings.Split(strings.Trim(e.path(), "/"), "/") {
+			var child *tocNode
+			for _, c := range node.Children {
+				if c.segment == segment {
+					child = c
+					break
+				}
+			}
+			if child == nil {
+				child = &tocNode{Path: "/" + segment, segment: segment}
+				node.Children = append(node.Children, child)
+			}
+			node = child
+		}
+		node.Entries = append(node.Entries, e)
+	}
+
+	for _, c := range root.Children {
+		c.compress(0)
+	}
+	return root.Children
+}
+
+func (n *tocNode) compress(depth int) {
+	for len(n.Entries) == 0 && len(n.Children) == 1 {
+		child := n.Children[0]
+		n.Path += child.Path
+		n.Entries = child.Entries
+		n.Children = child.Children
+	}
+
+	n.Indent = strings.Repeat("  ", depth)
+	for _, c := range n.Children {
+		c.compress(depth + 1)
+	}
+}
@@ -0,0 +1,106 @@
+package httpdoc
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSlugify(t *testing.T) {
+	cases := []struct {
+		heading string
+		want    string
+	}{
+		{"[200] POST /v1/user", "200-post-v1user"},
+		{"[200] GET /v1/user/{id}", "200-get-v1userid"},
+		{"[404] GET /v1/user/:id/items.json", "404-get-v1useriditemsjson"},
+		{"[200] GET /v1/user-items/some_thing", "200-get-v1user-itemssome_thing"},
+		{"[200] GET /v1/search?q=x", "200-get-v1searchqx"},
+		{"[200] GET /v1/ユーザー", "200-get-v1ユーザー"},
+	}
+
+	for _, tc := range cases {
+		if got := slugify(tc.heading); got != tc.want {
+			t.Fatalf("slugify(%q): got %q, want %q", tc.heading, got, tc.want)
+		}
+	}
+}
+
+func TestSetAnchors(t *testing.T) {
+	entries := []Entry{
+		{Method: "GET", Path: "/a/b", ResponseStatusCode: 200},
+		{Method: "GET", Path: "/ab", ResponseStatusCode: 200},
+		{Method: "GET", Path: "/a/b", ResponseStatusCode: 200},
+	}
+	setAnchors(entries)
+
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Anchor)
+	}
+	if want := []string{"200-get-ab", "200-get-ab-1", "200-get-ab-2"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestTocTree(t *testing.T) {
+	entries := []Entry{
+		{Method: "POST", Path: "/v1/user"},
+		{Method: "GET", Path: "/v1/user/{id}/items"},
+		{Method: "GET", Path: "/v1/health"},
+		{Method: "GET", Path: "/v2/user"},
+	}
+
+	var lines []string
+	var walk func(nodes []*tocNode)
+	walk = func(nodes []*tocNode) {
+		for _, n := range nodes {
+			lines = append(lines, n.Indent+n.Path+strings.Repeat("*", len(n.Entries)))
+			walk(n.Children)
+		}
+	}
+	walk(tocTree(entries))
+
+	want := []string{
+		"/v1",
+		"  /user*",
+		"    /{id}/items*",
+		"  /health*",
+		"/v2/user*",
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Fatalf("got %q, want %q", lines, want)
+	}
+}
+
+func TestDocument_GenerateDir_Anchors(t *testing.T) {
+	resetF := setEnv(t, EnvHTTPDoc, "1")
+	defer resetF()
+
+	dir, err := ioutil.TempDir("", "httpdoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Anchors collide in single file documentation but not in each page.
+	doc := &Document{
+		Entries: []Entry{
+			{Method: "GET", Path: "/a/b", ResponseStatusCode: 200},
+			{Method: "GET", Path: "/ab", ResponseStatusCode: 200},
+		},
+	}
+	if err := doc.GenerateDir(dir); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	index, _ := ioutil.ReadFile(filepath.Join(dir, "index.md"))
+	for _, want := range []string{"](get-a-b.md#200-get-ab)", "](get-ab.md#200-get-ab)"} {
+		if !strings.Contains(string(index), want) {
+			t.Fatalf("expect %q to contain %q", index, want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Package chidoc provides go-chi/chi middleware which records requests by httpdoc with the matched
+// route pattern and URL parameters.
+package chidoc // import "go.mercari.io/go-httpdoc/chidoc"
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	httpdoc "go.mercari.io/go-httpdoc"
+)
+
+// Middleware returns chi middleware which records requests in the document like httpdoc.Record.
+// The route pattern (e.g., `/users/{id}`) is used as Entry.PathTemplate and URL parameters are
+// used as Entry.PathParams. chi doesn't name routes, so Entry.RouteName is empty.
+//
+// Use it for the router to record all routes,
+//
+//   r := chi.NewRouter()
+//   r.Use(chidoc.Middleware(document, nil))
+//   r.Get("/users/{id}", getUser)
+//
+// or for each route to give the route its own option. Don't combine them for the same route, the
+// request is recorded twice otherwise.
+//
+//   r := chi.NewRouter()
+//   r.With(chidoc.Middleware(document, &httpdoc.RecordOption{Summary: "Get a user"})).Get("/users/{id}", getUser)
+//
+func Middleware(document *httpdoc.Document, opt *httpdoc.RecordOption) func(http.Handler) http.Handler {
+	o := httpdoc.RecordOption{}
+	if opt != nil {
+		o = *opt
+	}
+	o.RouteResolver = resolveRoute
+
+	return func(next http.Handler) http.Handler {
+		return httpdoc.Record(next, document, &o)
+	}
+}
+
+// resolveRoute returns the route from the routing context. The pattern is complete after the
+// handler returns because chi appends patterns of sub routers while routing.
+func resolveRoute(r *http.Request) *httpdoc.Route {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil || rctx.RoutePattern() == "" {
+		return nil
+	}
+
+	route := &httpdoc.Route{PathTemplate: rctx.RoutePattern()}
+	for i, key := range rctx.URLParams.Keys {
+		route.Params = append(route.Params, httpdoc.Data{
+			Name:  key,
+			Value: rctx.URLParams.Values[i],
+		})
+	}
+	return route
+}
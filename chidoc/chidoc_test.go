@@ -0,0 +1,46 @@
+package chidoc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-chi/chi"
+	httpdoc "go.mercari.io/go-httpdoc"
+)
+
+func TestMiddleware(t *testing.T) {
+	document := &httpdoc.Document{}
+
+	r := chi.NewRouter()
+	r.Use(Middleware(document, &httpdoc.RecordOption{Summary: "Users"}))
+	r.Route("/v1/users", func(r chi.Router) {
+		r.Get("/{id}/books/{bookID}", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{}`))
+		})
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/v1/users/1/books/2")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	if len(document.Entries) != 1 {
+		t.Fatalf("expect 1 entry: %d", len(document.Entries))
+	}
+	e := document.Entries[0]
+	if want := "/v1/users/{id}/books/{bookID}"; e.PathTemplate != want {
+		t.Fatalf("got %q, want %q", e.PathTemplate, want)
+	}
+	if want := []httpdoc.Data{{Name: "id", Value: "1"}, {Name: "bookID", Value: "2"}}; !reflect.DeepEqual(e.PathParams, want) {
+		t.Fatalf("got %#v, want %#v", e.PathParams, want)
+	}
+	if e.Summary != "Users" {
+		t.Fatalf("got %q, want %q", e.Summary, "Users")
+	}
+}
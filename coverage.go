@@ -0,0 +1,190 @@
+package httpdoc
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// Coverage is documentation coverage of the document. Normally, you don't need to modify this.
+// All fields are exported just for templating.
+type Coverage struct {
+	// Routes is coverage of registered routes (see Document.Routes).
+	Routes []RouteCoverage
+
+	// Endpoints is coverage of each recorded endpoint (method, path and operation).
+	Endpoints []EndpointCoverage
+}
+
+// RouteCoverage is coverage of a registered route.
+type RouteCoverage struct {
+	// Route is the registered route, e.g., `GET /v1/users/{id}`.
+	Route string
+
+	// Count is the number of recorded entries of the route. The route is never exercised by tests
+	// if it's 0.
+	Count int
+}
+
+// EndpointCoverage is coverage of a recorded endpoint.
+type EndpointCoverage struct {
+	// Endpoint is method, path and operation of the endpoint, e.g., `GET /v1/users/{id}`.
+	Endpoint string
+
+	// Described is true if any entry of the endpoint has summary or description.
+	Described bool
+
+	// Fields is the number of documented path parameters, request parameters and request & response
+	// fields of the endpoint. Validated is the number of them validated by Validator.
+	Fields    int
+	Validated int
+
+	// Unvalidated is list of fields which are not validated, e.g., "response field `name`".
+	Unvalidated []string
+}
+
+// Percent returns documentation coverage in percent. Registered routes which are recorded, endpoints
+// which are described and fields which are validated are counted as covered. It's 100 if there is
+// nothing to cover.
+func (c Coverage) Percent() float64 {
+	var covered, total int
+	for _, r := range c.Routes {
+		total++
+		if r.Count > 0 {
+			covered++
+		}
+	}
+	for _, e := range c.Endpoints {
+		total += 1 + e.Fields
+		covered += e.Validated
+		if e.Described {
+			covered++
+		}
+	}
+	if total == 0 {
+		return 100
+	}
+	return float64(covered) * 100 / float64(total)
+}
+
+// String returns the report of uncovered routes, endpoints and fields.
+func (c Coverage) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "documentation coverage: %.1f%%\n", c.Percent())
+	for _, r := range c.Routes {
+		if r.Count == 0 {
+			fmt.Fprintf(&buf, "  %s: not recorded\n", r.Route)
+		}
+	}
+	for _, e := range c.Endpoints {
+		if !e.Described {
+			fmt.Fprintf(&buf, "  %s: no description\n", e.Endpoint)
+		}
+		for _, f := range e.Unvalidated {
+			fmt.Fprintf(&buf, "  %s: %s is not validated\n", e.Endpoint, f)
+		}
+	}
+	return buf.String()
+}
+
+// Coverage returns documentation coverage. It reports registered routes which are never exercised
+// by tests and recorded endpoints which lack description or validated fields. Headers are not
+// counted because most of them are set by the server or the client.
+func (d *Document) Coverage() Coverage {
+	var c Coverage
+	for _, route := range d.Routes {
+		p := parseRoutePattern(route)
+		rc := RouteCoverage{Route: route}
+		for _, e := range d.Entries {
+			if !p.matchMethod(e.Method) {
+				continue
+			}
+			if _, ok := p.params(e.Path); ok {
+				rc.Count++
+			}
+		}
+		c.Routes = append(c.Routes, rc)
+	}
+
+	for _, group := range groupEntries(d.Entries) {
+		ec := EndpointCoverage{Endpoint: group[0].endpoint()}
+
+		var fields []string
+		validated := make(map[string]bool)
+		seen := make(map[string]bool)
+		for _, e := range group {
+			if e.Summary != "" || e.Description != "" {
+				ec.Described = true
+			}
+			for _, f := range e.validated {
+				validated[f] = true
+			}
+			for _, f := range e.fields() {
+				if !seen[f] {
+					seen[f] = true
+					fields = append(fields, f)
+				}
+			}
+		}
+
+		ec.Fields = len(fields)
+		for _, f := range fields {
+			if validated[f] {
+				ec.Validated++
+				continue
+			}
+			ec.Unvalidated = append(ec.Unvalidated, f)
+		}
+		c.Endpoints = append(c.Endpoints, ec)
+	}
+	return c
+}
+
+// CheckCoverage fails the test if documentation coverage is less than min percent. The report of
+// uncovered routes, endpoints and fields is written in the failure message. Call it at the end of
+// the test after all requests are recorded. Since TestMain has no *testing.T, use Coverage there.
+//
+//   func TestMain(m *testing.M) {
+//       code := m.Run()
+//       if c := document.Coverage(); c.Percent() < 80 {
+//           fmt.Fprint(os.Stderr, c)
+//           code = 1
+//       }
+//       os.Exit(code)
+//   }
+//
+func (d *Document) CheckCoverage(t *testing.T, min float64) {
+	c := d.Coverage()
+	if percent := c.Percent(); percent < min {
+		tFatalf(t, "documentation coverage %.1f%% is less than %.1f%%\n%s", percent, min, c)
+	}
+}
+
+// fields returns labels of the documented fields of the entry.
+func (e Entry) fields() []string {
+	return coverageFields(e.PathParams, e.RequestParams, e.RequestFields, e.ResponseFields)
+}
+
+// validated returns labels of the fields which the validator validated.
+func (v *Validator) validated() []string {
+	return coverageFields(v.pathParams, v.requestParams, v.requestFields, v.responseFields)
+}
+
+// coverageFields returns labels of path parameters, request parameters, request fields and response fields.
+func coverageFields(pathParams, requestParams, requestFields, responseFields []Data) []string {
+	var fields []string
+	for _, x := range []struct {
+		kind string
+		data []Data
+	}{
+		{"path parameter", pathParams},
+		{"request parameter", requestParams},
+		{"request field", requestFields},
+		{"response field", responseFields},
+	} {
+		for _, d := range x.data {
+			fields = append(fields, fmt.Sprintf("%s `%s`", x.kind, d.Name))
+		}
+	}
+	return fields
+}
@@ -0,0 +1,136 @@
+package httpdoc
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Coverage(t *testing.T) {
+	document := &Document{}
+	document.Routes = []string{"POST /v1/users"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":1,"name":"tcnksm"}`))
+	})
+
+	handler := RecordAll(mux, document, map[string]*RecordOption{
+		"GET /v1/users/{id}": {
+			Summary: "Get a user",
+			WithValidate: func(validator *Validator) {
+				validator.ResponseBody(t, []TestCase{
+					{Target: "id", Expected: float64(1), Description: "User ID"},
+				}, nil)
+			},
+		},
+		"DELETE /v1/users/{id}": {},
+	})
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	for _, path := range []string{"/v1/users/1", "/v1/users/2"} {
+		res, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		res.Body.Close()
+	}
+
+	if want := []string{"POST /v1/users", "DELETE /v1/users/{id}", "GET /v1/users/{id}"}; !reflect.DeepEqual(document.Routes, want) {
+		t.Fatalf("got %q, want %q", document.Routes, want)
+	}
+
+	got := document.Coverage()
+	want := Coverage{
+		Routes: []RouteCoverage{
+			{Route: "POST /v1/users"},
+			{Route: "DELETE /v1/users/{id}"},
+			{Route: "GET /v1/users/{id}", Count: 2},
+		},
+		Endpoints: []EndpointCoverage{
+			{
+				Endpoint:    "GET /v1/users/{id}",
+				Described:   true,
+				Fields:      2,
+				Validated:   1,
+				Unvalidated: []string{"path parameter `id`"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("\ngot  %#v\nwant %#v", got, want)
+	}
+
+	// 1 route, 1 description and 1 field are covered in 3 routes, 1 endpoint and 2 fields.
+	if got, want := got.Percent(), float64(50); got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestCoverage_Percent_Empty(t *testing.T) {
+	if got := (Coverage{}).Percent(); got != 100 {
+		t.Fatalf("got %v, want 100", got)
+	}
+}
+
+func TestDocument_CheckCoverage(t *testing.T) {
+	var buf bytes.Buffer
+	tFatalf = fprintFatalFunc(&buf)
+	defer func() {
+		tFatalf = defaultFatalFunc
+	}()
+
+	document := &Document{
+		Routes: []string{"GET /v1/users", "/v1/users/{id}"},
+		Entries: []Entry{
+			{Method: "GET", Path: "/v1/users", Summary: "List users"},
+		},
+	}
+
+	document.CheckCoverage(t, 50)
+	if buf.Len() != 0 {
+		t.Fatalf("expect coverage to be enough: %s", buf.String())
+	}
+
+	document.CheckCoverage(t, 80)
+	want := "documentation coverage 66.7% is less than 80.0%\ndocumentation coverage: 66.7%\n  /v1/users/{id}: not recorded\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestDocument_Generate_Coverage(t *testing.T) {
+	doc := &Document{
+		ShowCoverage: true,
+		Routes:       []string{"GET /v1/user", "DELETE /v1/user"},
+		Entries: []Entry{
+			{
+				Method:         "GET",
+				Path:           "/v1/user",
+				ResponseFields: []Data{{Name: "id"}, {Name: "name"}},
+				validated:      []string{"response field `id`"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := doc.generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"Documentation coverage: 40.0%",
+		"| DELETE /v1/user | not recorded |",
+		"| GET /v1/user | no | 1/2 | response field `name` |",
+	} {
+		if got := buf.String(); !strings.Contains(got, want) {
+			t.Fatalf("expect %q to contain %q", got, want)
+		}
+	}
+}
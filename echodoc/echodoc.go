@@ -0,0 +1,72 @@
+// Package echodoc provides echo middleware which records requests by httpdoc with the matched route
+// path, route name and path parameters.
+package echodoc // import "go.mercari.io/go-httpdoc/echodoc"
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo"
+	httpdoc "go.mercari.io/go-httpdoc"
+)
+
+// Middleware returns echo middleware which records requests in the document like httpdoc.Record.
+// The route path (e.g., `/users/:id`) is used as Entry.PathTemplate, the route name is used as
+// Entry.RouteName and path parameters are used as Entry.PathParams. Errors which handlers return
+// are handled by the middleware, so that error responses are recorded.
+//
+//   e := echo.New()
+//   e.Use(echodoc.Middleware(document, nil))
+//   e.GET("/users/:id", getUser).Name = "getUser"
+//
+func Middleware(document *httpdoc.Document, opt *httpdoc.RecordOption) echo.MiddlewareFunc {
+	base := httpdoc.RecordOption{}
+	if opt != nil {
+		base = *opt
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			o := base
+			o.RouteResolver = func(*http.Request) *httpdoc.Route {
+				return resolveRoute(c)
+			}
+
+			res := c.Response()
+			writer := res.Writer
+			httpdoc.Record(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Handlers write response through the recorder.
+				res.Writer = w
+				c.SetRequest(r)
+				if err := next(c); err != nil {
+					c.Error(err)
+				}
+				res.Writer = writer
+			}), document, &o).ServeHTTP(writer, c.Request())
+			return nil
+		}
+	}
+}
+
+func resolveRoute(c echo.Context) *httpdoc.Route {
+	template := c.Path()
+	if template == "" {
+		return nil
+	}
+
+	route := &httpdoc.Route{PathTemplate: template}
+	method := c.Request().Method
+	for _, r := range c.Echo().Routes() {
+		if r.Method == method && r.Path == template {
+			route.Name = r.Name
+			break
+		}
+	}
+
+	values := c.ParamValues()
+	for i, name := range c.ParamNames() {
+		if i < len(values) {
+			route.Params = append(route.Params, httpdoc.Data{Name: name, Value: values[i]})
+		}
+	}
+	return route
+}
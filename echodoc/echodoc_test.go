@@ -0,0 +1,53 @@
+package echodoc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/labstack/echo"
+	httpdoc "go.mercari.io/go-httpdoc"
+)
+
+func TestMiddleware(t *testing.T) {
+	document := &httpdoc.Document{}
+
+	e := echo.New()
+	e.Use(Middleware(document, nil))
+	e.GET("/v1/users/:id/books/:bookID", func(c echo.Context) error {
+		if c.Param("bookID") == "0" {
+			return echo.NewHTTPError(http.StatusNotFound, "book not found")
+		}
+		return c.JSON(http.StatusOK, map[string]string{"id": c.Param("bookID")})
+	}).Name = "getBook"
+
+	ts := httptest.NewServer(e)
+	defer ts.Close()
+
+	for _, path := range []string{"/v1/users/1/books/2", "/v1/users/1/books/0"} {
+		res, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		res.Body.Close()
+	}
+
+	if len(document.Entries) != 2 {
+		t.Fatalf("expect 2 entries: %d", len(document.Entries))
+	}
+	entry := document.Entries[0]
+	if want := "/v1/users/:id/books/:bookID"; entry.PathTemplate != want {
+		t.Fatalf("got %q, want %q", entry.PathTemplate, want)
+	}
+	if entry.RouteName != "getBook" {
+		t.Fatalf("got %q, want %q", entry.RouteName, "getBook")
+	}
+	if want := []httpdoc.Data{{Name: "id", Value: "1"}, {Name: "bookID", Value: "2"}}; !reflect.DeepEqual(entry.PathParams, want) {
+		t.Fatalf("got %#v, want %#v", entry.PathParams, want)
+	}
+
+	if got := document.Entries[1].ResponseStatusCode; got != http.StatusNotFound {
+		t.Fatalf("expect error response to be recorded: got %d", got)
+	}
+}
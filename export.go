@@ -0,0 +1,149 @@
+package httpdoc
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// defaultBaseURL is used in exported requests when Document.BaseURL is empty.
+const defaultBaseURL = "http://localhost"
+
+// defaultSecrets is list of header and parameter names whose values are treated as secret by default.
+var defaultSecrets = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Auth-Token",
+	"access_token",
+	"api_key",
+	"apikey",
+	"token",
+}
+
+var versionRegexp = regexp.MustCompile(`^v[0-9]+`)
+
+var nonWordRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+// baseURL returns the base URL used in exported requests.
+func (d *Document) baseURL() string {
+	if d.BaseURL == "" {
+		return defaultBaseURL
+	}
+	return strings.TrimRight(d.BaseURL, "/")
+}
+
+// isSecret reports whether the value of the given header or parameter name is secret.
+// API keys declared in Document.SecuritySchemes are also secret.
+func (d *Document) isSecret(name string) bool {
+	for _, list := range [][]string{defaultSecrets, d.Secrets, d.securityParamNames()} {
+		for _, secret := range list {
+			if strings.EqualFold(secret, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// variableName returns the variable name used for a secret header or parameter in exported requests.
+func variableName(name string) string {
+	return strings.Trim(nonWordRegexp.ReplaceAllString(strings.ToLower(name), "_"), "_")
+}
+
+// requestParams returns request parameters with the values which handler actually received.
+// This is needed because documented value can be different from actual value. Parameters which
+// are documented but not actually sent are not included.
+func (e *Entry) requestParams() []Data {
+	if e.record == nil {
+		return e.RequestParams
+	}
+
+	params := make([]Data, 0, len(e.RequestParams))
+	for _, p := range e.RequestParams {
+		v, ok := e.record.requestParams[p.Name]
+		if !ok || len(v) == 0 {
+			continue
+		}
+		p.Value = v[0]
+		params = append(params, p)
+	}
+	return params
+}
+
+// requestHeaders returns request headers with the values which handler actually received.
+// Excluded headers and headers which are documented but not actually sent are not included.
+// If the request example is json decoded from protocol buffer encoded body, Content-Type is
+// `application/json` since the example is sent instead of the original body.
+func (e *Entry) requestHeaders() []Data {
+	headers := make([]Data, 0, len(e.RequestHeaders))
+	for _, h := range e.RequestHeaders {
+		if e.record != nil {
+			v, ok := e.record.requestHeaders[h.Name]
+			if !ok || len(v) == 0 {
+				continue
+			}
+			h.Value = v[0]
+		}
+		if e.requestDecoded && http.CanonicalHeaderKey(h.Name) == "Content-Type" {
+			h.Value = "application/json"
+		}
+		headers = append(headers, h)
+	}
+	return headers
+}
+
+// pathPrefix returns the path prefix which is used to group entries, e.g., in exported collection
+// folders. It's the first path segment. If the first segment is a version (e.g., `v1`), then the
+// second segment is also included.
+func pathPrefix(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if segments[0] == "" {
+		return "/"
+	}
+	if versionRegexp.MatchString(segments[0]) && len(segments) > 1 {
+		return segments[0] + "/" + segments[1]
+	}
+	return segments[0]
+}
+
+// groupEntries groups entries which have the same method, path and operation. Groups are sorted by
+// the order of first appearance.
+func groupEntries(entries []Entry) [][]Entry {
+	var groups [][]Entry
+	index := make(map[string]int)
+	for _, e := range entries {
+		key := e.endpoint()
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], e)
+	}
+	return groups
+}
+
+// contentType returns the value of Content-Type header in the given headers.
+func contentType(headers []Data) string {
+	for _, h := range headers {
+		if strings.EqualFold(h.Name, "Content-Type") {
+			return fmt.Sprint(h.Value)
+		}
+	}
+	return ""
+}
+
+func sortedSet(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,138 @@
+package httpdoc
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+)
+
+var testExportEntries = []Entry{
+	{
+		Description: "Create a new user",
+		Method:      "POST",
+		Path:        "/v1/user",
+		RequestParams: []Data{
+			{Name: "pretty", Value: "true"},
+			{Name: "token", Value: "12345"},
+		},
+		RequestHeaders: []Data{
+			{Name: "Content-Type", Value: "application/json"},
+			{Name: "X-Version", Value: "2"},
+		},
+		RequestExample:     `{"name": "tcnksm"}`,
+		ResponseStatusCode: http.StatusOK,
+		ResponseHeaders: []Data{
+			{Name: "Content-Type", Value: "application/json"},
+		},
+		ResponseExample: `{"id": 1, "name": "tcnksm"}`,
+	},
+	{
+		Description:        "Create a new user",
+		Method:             "POST",
+		Path:               "/v1/user",
+		RequestExample:     `{}`,
+		ResponseStatusCode: http.StatusBadRequest,
+		ResponseExample:    `{"message": "name is required"}`,
+	},
+	{
+		Method:             "GET",
+		Path:               "/v1/user/1",
+		ResponseStatusCode: http.StatusOK,
+	},
+	{
+		Method:             "GET",
+		Path:               "/health",
+		ResponseStatusCode: http.StatusOK,
+	},
+}
+
+// recordProtoEntries records the request to protocol buffer endpoint.
+func recordProtoEntries(t *testing.T) []Entry {
+	doc := &Document{}
+	ts := httptest.NewServer(Record(http.HandlerFunc(testHandlerProto), doc, &RecordOption{
+		WithProtoBuffer: &ProtoBufferOption{
+			RequestMessage:  func() proto.Message { return &UserProtoRequest{} },
+			ResponseMessage: func() proto.Message { return &UserProtoResponse{} },
+		},
+	}))
+	defer ts.Close()
+
+	body, _ := proto.Marshal(&UserProtoRequest{Id: 7089})
+	res, err := http.Post(ts.URL+"/v1/user", "application/protobuf", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+	return doc.Entries
+}
+
+func TestPathPrefix(t *testing.T) {
+	cases := []struct {
+		path string
+		want string
+	}{
+		{"/", "/"},
+		{"/health", "health"},
+		{"/v1", "v1"},
+		{"/v1/user", "v1/user"},
+		{"/v1/user/1", "v1/user"},
+		{"/users/1", "users"},
+	}
+
+	for _, tc := range cases {
+		if got := pathPrefix(tc.path); got != tc.want {
+			t.Fatalf("pathPrefix(%q): got %q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestVariableName(t *testing.T) {
+	cases := []struct {
+		name string
+		want string
+	}{
+		{"Authorization", "authorization"},
+		{"X-Api-Key", "x_api_key"},
+		{"access_token", "access_token"},
+	}
+
+	for _, tc := range cases {
+		if got := variableName(tc.name); got != tc.want {
+			t.Fatalf("variableName(%q): got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDocument_IsSecret(t *testing.T) {
+	doc := &Document{Secrets: []string{"X-Session"}}
+	for _, name := range []string{"authorization", "Cookie", "token", "x-session"} {
+		if !doc.isSecret(name) {
+			t.Fatalf("expect %q to be secret", name)
+		}
+	}
+	if doc.isSecret("Content-Type") {
+		t.Fatalf("expect Content-Type not to be secret")
+	}
+}
+
+func TestGroupEntries(t *testing.T) {
+	groups := groupEntries(testExportEntries)
+
+	var got [][]int
+	for _, group := range groups {
+		var codes []int
+		for _, e := range group {
+			codes = append(codes, e.ResponseStatusCode)
+		}
+		got = append(got, codes)
+	}
+
+	want := [][]int{{200, 400}, {200}, {200}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,337 @@
+package httpdoc
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type pathElemKind int
+
+const (
+	// pathField is a struct field or a map key, e.g., `.Name`.
+	pathField pathElemKind = iota
+
+	// pathIndex is an index of a slice or an array, or an integer map key, e.g., `[0]`.
+	pathIndex
+
+	// pathKey is a quoted map key, e.g., `["email"]`.
+	pathKey
+
+	// pathWildcard is every element of a slice, an array or a map, e.g., `[*]`.
+	pathWildcard
+)
+
+type pathElem struct {
+	kind  pathElemKind
+	name  string
+	index int
+}
+
+func (e pathElem) String() string {
+	switch e.kind {
+	case pathIndex:
+		return "[" + strconv.Itoa(e.index) + "]"
+	case pathKey:
+		return "[" + strconv.Quote(e.name) + "]"
+	case pathWildcard:
+		return "[*]"
+	}
+	return e.name
+}
+
+// fieldPath is a parsed TestCase.Target, e.g., `Items[*].Name` or `Preference["email"]`.
+type fieldPath []pathElem
+
+// parseFieldPath parses the target. Fields are separated by `.` and followed by indices (`[0]`),
+// quoted map keys (`["key"]`) or wildcards (`[*]`).
+func parseFieldPath(target string) (fieldPath, error) {
+	var path fieldPath
+	s := target
+	for i := 0; s != ""; i++ {
+		switch {
+		case s[0] == '[':
+			elem, rest, err := parseBracket(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid field path %q: %s", target, err)
+			}
+			path = append(path, elem)
+			s = rest
+		case s[0] == '.' && i > 0:
+			s = s[1:]
+			fallthrough
+		default:
+			n := strings.IndexAny(s, ".[]")
+			if n < 0 {
+				n = len(s)
+			}
+			if n == 0 {
+				return nil, fmt.Errorf("invalid field path %q: field name is empty", target)
+			}
+			path = append(path, pathElem{kind: pathField, name: s[:n]})
+			s = s[n:]
+		}
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("invalid field path %q: path is empty", target)
+	}
+	return path, nil
+}
+
+// parseBracket parses the bracket at the beginning of s and returns the rest.
+func parseBracket(s string) (pathElem, string, error) {
+	if len(s) > 1 && (s[1] == '"' || s[1] == '`') {
+		end := 2
+		for ; end < len(s) && s[end] != s[1]; end++ {
+			if s[end] == '\\' && s[1] == '"' {
+				end++
+			}
+		}
+		if end+1 >= len(s) || s[end+1] != ']' {
+			return pathElem{}, "", fmt.Errorf("unclosed map key")
+		}
+		key, err := strconv.Unquote(s[1 : end+1])
+		if err != nil {
+			return pathElem{}, "", err
+		}
+		return pathElem{kind: pathKey, name: key}, s[end+2:], nil
+	}
+
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return pathElem{}, "", fmt.Errorf("unclosed index")
+	}
+	inner := s[1:end]
+	if inner == "*" {
+		return pathElem{kind: pathWildcard}, s[end+1:], nil
+	}
+	index, err := strconv.Atoi(inner)
+	if err != nil || index < 0 {
+		return pathElem{}, "", fmt.Errorf("invalid index %q", inner)
+	}
+	return pathElem{kind: pathIndex, index: index}, s[end+1:], nil
+}
+
+// values returns the values at the path in v. If the path doesn't have wildcards, exactly one
+// value is returned and it's nil when the value is not found. Wildcards matching empty slices or
+// maps don't return values.
+func (p fieldPath) values(v interface{}) []interface{} {
+	current := []reflect.Value{reflect.ValueOf(v)}
+	for _, e := range p {
+		var next []reflect.Value
+		for _, rv := range current {
+			next = append(next, e.step(rv)...)
+		}
+		current = next
+	}
+
+	values := make([]interface{}, len(current))
+	for i, rv := range current {
+		if rv.IsValid() && rv.CanInterface() {
+			values[i] = rv.Interface()
+		}
+	}
+	return values
+}
+
+// step returns the values the element points in rv. Invalid value is returned when it's not found.
+func (e pathElem) step(rv reflect.Value) []reflect.Value {
+	notFound := []reflect.Value{{}}
+
+	// Messages which don't have Go struct fields (e.g., dynamic messages) are inspected by protoreflect.
+	if e.kind == pathField && rv.IsValid() && rv.CanInterface() && !(rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		if m, ok := rv.Interface().(protoreflect.ProtoMessage); ok && !hasField(rv, e.name) {
+			return []reflect.Value{protoField(m.ProtoReflect(), e.name)}
+		}
+	}
+
+	for rv.IsValid() && (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) {
+		if rv.IsNil() {
+			return notFound
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return notFound
+	}
+
+	switch e.kind {
+	case pathField:
+		switch rv.Kind() {
+		case reflect.Struct:
+			return []reflect.Value{rv.FieldByName(e.name)}
+		case reflect.Map:
+			return []reflect.Value{mapIndex(rv, e.name)}
+		}
+	case pathKey:
+		if rv.Kind() == reflect.Map {
+			return []reflect.Value{mapIndex(rv, e.name)}
+		}
+	case pathIndex:
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			if e.index < rv.Len() {
+				return []reflect.Value{rv.Index(e.index)}
+			}
+		case reflect.Map:
+			return []reflect.Value{mapIndex(rv, strconv.Itoa(e.index))}
+		}
+	case pathWildcard:
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			values := make([]reflect.Value, rv.Len())
+			for i := range values {
+				values[i] = rv.Index(i)
+			}
+			return values
+		case reflect.Map:
+			keys := rv.MapKeys()
+			sort.Sort(byKey(keys))
+			values := make([]reflect.Value, len(keys))
+			for i, k := range keys {
+				values[i] = rv.MapIndex(k)
+			}
+			return values
+		}
+	}
+	return notFound
+}
+
+// hasField reports whether rv is a struct or a pointer to a struct which has the field.
+func hasField(rv reflect.Value, name string) bool {
+	t := rv.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	_, ok := t.FieldByName(name)
+	return ok
+}
+
+// mapIndex returns the value of the map for the key. The key is converted to the key type of the map
+// if it's a string or an integer type.
+func mapIndex(m reflect.Value, key string) reflect.Value {
+	kt := m.Type().Key()
+	switch kt.Kind() {
+	case reflect.String:
+		return m.MapIndex(reflect.ValueOf(key).Convert(kt))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(key, 10, 64)
+		if err != nil {
+			return reflect.Value{}
+		}
+		return m.MapIndex(reflect.ValueOf(i).Convert(kt))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return reflect.Value{}
+		}
+		return m.MapIndex(reflect.ValueOf(u).Convert(kt))
+	}
+	return reflect.Value{}
+}
+
+type byKey []reflect.Value
+
+func (k byKey) Len() int { return len(k) }
+func (k byKey) Less(i, j int) bool {
+	return fmt.Sprint(k[i].Interface()) < fmt.Sprint(k[j].Interface())
+}
+func (k byKey) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+
+// Path returns the segments of the field name which are rendered as nested fields. Indices, map keys
+// and wildcards belong to the preceding field, e.g., `Items[*].Name` is `Items[*]` and `Name`.
+func (d Data) Path() []string {
+	path, err := parseFieldPath(d.Name)
+	if err != nil {
+		return []string{d.Name}
+	}
+
+	var segments []string
+	for _, e := range path {
+		if e.kind == pathField || len(segments) == 0 {
+			segments = append(segments, e.String())
+			continue
+		}
+		segments[len(segments)-1] += e.String()
+	}
+	return segments
+}
+
+// fieldRow is a row of request or response fields in documentation. Data is nil for parent fields
+// which are not validated themselves.
+type fieldRow struct {
+	Indent string
+	Name   string
+	Data   *Data
+}
+
+type fieldNode struct {
+	name     string
+	data     []*Data
+	children []*fieldNode
+}
+
+// fieldTree returns the fields as rows of an indented tree. Fields are ordered by first appearance
+// and parent fields are inserted before their children.
+func fieldTree(fields []Data) []fieldRow {
+	root := &fieldNode{}
+	for i := range fields {
+		node := root
+		for _, segment := range fields[i].Path() {
+			var child *fieldNode
+			for _, c := range node.children {
+				if c.name == segment {
+					child = c
+					break
+				}
+			}
+			if child == nil {
+				child = &fieldNode{name: segment}
+				node.children = append(node.children, child)
+			}
+			node = child
+		}
+		node.data = append(node.data, &fields[i])
+	}
+
+	var rows []fieldRow
+	var walk func(nodes []*fieldNode, depth int)
+	walk = func(nodes []*fieldNode, depth int) {
+		for _, n := range nodes {
+			indent := strings.Repeat("&emsp;", depth)
+			if len(n.data) == 0 {
+				rows = append(rows, fieldRow{Indent: indent, Name: n.name})
+			}
+			for _, data := range n.data {
+				rows = append(rows, fieldRow{Indent: indent, Name: n.name, Data: data})
+			}
+			walk(n.children, depth+1)
+		}
+	}
+	walk(root.children, 0)
+	return rows
+}
+
+// fieldsTable is request or response fields table in documentation. Example and Constraints report
+// whether the columns are written.
+type fieldsTable struct {
+	Example     bool
+	Constraints bool
+	Rows        []fieldRow
+}
+
+func fieldTable(fields []Data) fieldsTable {
+	table := fieldsTable{Rows: fieldTree(fields)}
+	for _, f := range fields {
+		table.Example = table.Example || f.Example != ""
+		table.Constraints = table.Constraints || len(f.Constraints) > 0
+	}
+	return table
+}
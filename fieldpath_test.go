@@ -0,0 +1,176 @@
+package httpdoc
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Order struct {
+	Items  []Item
+	Labels map[string]string
+	Counts map[int]int
+}
+
+type Item struct {
+	Name  string
+	Price int
+}
+
+func TestParseFieldPath(t *testing.T) {
+	cases := []struct {
+		target string
+		want   string
+	}{
+		{"Name", "Name"},
+		{"Setting.SNS.Twitter", "Setting SNS Twitter"},
+		{"Items[0].Name", "Items [0] Name"},
+		{"Items[*].Name", "Items [*] Name"},
+		{`Preference["email"]`, `Preference ["email"]`},
+		{"Preference[`a.b`]", `Preference ["a.b"]`},
+		{`Labels["a\"]"]`, `Labels ["a\"]"]`},
+		{"[1].Name", "[1] Name"},
+	}
+
+	for _, tc := range cases {
+		path, err := parseFieldPath(tc.target)
+		if err != nil {
+			t.Fatalf("%s: err: %s", tc.target, err)
+		}
+		var got []string
+		for _, e := range path {
+			got = append(got, e.String())
+		}
+		if strings.Join(got, " ") != tc.want {
+			t.Fatalf("%s: got %q, want %q", tc.target, strings.Join(got, " "), tc.want)
+		}
+	}
+
+	for _, target := range []string{"", ".Name", "Items[", "Items[a]", "Items[-1]", `Labels["a]`, "Items..Name", "Items]"} {
+		if _, err := parseFieldPath(target); err == nil {
+			t.Fatalf("%q: expect to fail", target)
+		}
+	}
+}
+
+func TestFieldPath_Values(t *testing.T) {
+	order := &Order{
+		Items:  []Item{{Name: "apple", Price: 100}, {Name: "banana", Price: 100}},
+		Labels: map[string]string{"b": "y", "a": "x"},
+		Counts: map[int]int{1: 10},
+	}
+
+	cases := []struct {
+		target string
+		want   []interface{}
+	}{
+		{"Items[1].Name", []interface{}{"banana"}},
+		{"Items[*].Name", []interface{}{"apple", "banana"}},
+		{"Items[*].Price", []interface{}{100, 100}},
+		{`Labels["a"]`, []interface{}{"x"}},
+		{"Labels.b", []interface{}{"y"}},
+		{"Labels[*]", []interface{}{"x", "y"}},
+		{"Counts[1]", []interface{}{10}},
+		{"Items[2].Name", []interface{}{nil}},
+		{`Labels["c"]`, []interface{}{nil}},
+		{"Unknown.Name", []interface{}{nil}},
+	}
+
+	for _, tc := range cases {
+		path, err := parseFieldPath(tc.target)
+		if err != nil {
+			t.Fatalf("%s: err: %s", tc.target, err)
+		}
+		if got := path.values(order); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: got %#v, want %#v", tc.target, got, tc.want)
+		}
+	}
+
+	// Wildcards matching nothing don't return values.
+	path, _ := parseFieldPath("Items[*].Name")
+	if got := path.values(&Order{}); len(got) != 0 {
+		t.Fatalf("expect no values: %#v", got)
+	}
+}
+
+func TestValidateFields_Wildcard(t *testing.T) {
+	var buf bytes.Buffer
+	tFatalf = fprintFatalFunc(&buf)
+
+	order := &Order{Items: []Item{{Name: "apple", Price: 100}, {Name: "banana", Price: 200}}}
+	validator := newValidator()
+	validator.validateFields(t, []TestCase{
+		NewTestCase("Items[*].Price", 100, "price"),
+	}, order, &[]Data{})
+
+	if got, want := buf.String(), "price: got 200(int), want 100(int)"; !strings.Contains(got, want) {
+		t.Fatalf("expect %q to contain %q", got, want)
+	}
+
+	buf.Reset()
+	validator.validateFields(t, []TestCase{
+		NewTestCase("Items[x]", 100, ""),
+	}, order, &[]Data{})
+	if got, want := buf.String(), `invalid field path "Items[x]"`; !strings.Contains(got, want) {
+		t.Fatalf("expect %q to contain %q", got, want)
+	}
+}
+
+func TestFieldTree(t *testing.T) {
+	fields := []Data{
+		{Name: "ID", Value: 1},
+		{Name: "Items[*].Name", Value: "apple"},
+		{Name: "Items[*].Price", Value: 100},
+		{Name: "Setting", Value: "x"},
+		{Name: "Setting.SNS.Twitter", Value: "@deeeet"},
+	}
+
+	var got []string
+	for _, row := range fieldTree(fields) {
+		line := row.Indent + row.Name
+		if row.Data != nil {
+			line += "*"
+		}
+		got = append(got, line)
+	}
+
+	want := []string{
+		"ID*",
+		"Items[*]",
+		"&emsp;Name*",
+		"&emsp;Price*",
+		"Setting*",
+		"&emsp;SNS",
+		"&emsp;&emsp;Twitter*",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestDocument_Generate_NestedFields(t *testing.T) {
+	doc := &Document{
+		Entries: []Entry{
+			{
+				Method:             "GET",
+				Path:               "/v1/orders/1",
+				ResponseStatusCode: 200,
+				ResponseFields: []Data{
+					{Name: "Items[*].Name", Value: "apple", Description: "item name"},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := doc.generate(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, want := range []string{"| Items[*] |  |  |  |  |\n", "| &emsp;Name |  |  | apple | item name |\n"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expect %q to contain %q", buf.String(), want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Package gindoc provides gin middleware which records requests by httpdoc with the matched route
+// path and path parameters.
+package gindoc // import "go.mercari.io/go-httpdoc/gindoc"
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	httpdoc "go.mercari.io/go-httpdoc"
+)
+
+// Middleware returns gin middleware which records requests in the document like httpdoc.Record.
+// The route path (e.g., `/users/:id`) is used as Entry.PathTemplate and path parameters are used
+// as Entry.PathParams. gin doesn't name routes, so Entry.RouteName is empty.
+//
+//   r := gin.New()
+//   r.GET("/users/:id", gindoc.Middleware(document, &httpdoc.RecordOption{Summary: "Get a user"}), getUser)
+//
+func Middleware(document *httpdoc.Document, opt *httpdoc.RecordOption) gin.HandlerFunc {
+	base := httpdoc.RecordOption{}
+	if opt != nil {
+		base = *opt
+	}
+
+	return func(c *gin.Context) {
+		o := base
+		o.RouteResolver = func(*http.Request) *httpdoc.Route {
+			return resolveRoute(c)
+		}
+
+		writer := c.Writer
+		httpdoc.Record(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Handlers write response through the recorder.
+			c.Request = r
+			c.Writer = &responseWriter{ResponseWriter: writer, w: w}
+			c.Next()
+			c.Writer = writer
+		}), document, &o).ServeHTTP(writer, c.Request)
+	}
+}
+
+// responseWriter is gin.ResponseWriter which writes response through the recorder. Status and
+// size are still tracked by the original writer because the recorder writes to it.
+type responseWriter struct {
+	gin.ResponseWriter
+	w http.ResponseWriter
+}
+
+func (w *responseWriter) Write(buf []byte) (int, error) {
+	return w.w.Write(buf)
+}
+
+func (w *responseWriter) WriteString(s string) (int, error) {
+	return w.w.Write([]byte(s))
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	w.w.WriteHeader(code)
+}
+
+func resolveRoute(c *gin.Context) *httpdoc.Route {
+	template := c.FullPath()
+	if template == "" {
+		return nil
+	}
+
+	route := &httpdoc.Route{PathTemplate: template}
+	for _, p := range c.Params {
+		route.Params = append(route.Params, httpdoc.Data{Name: p.Key, Value: p.Value})
+	}
+	return route
+}
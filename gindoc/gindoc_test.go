@@ -0,0 +1,49 @@
+package gindoc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	httpdoc "go.mercari.io/go-httpdoc"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	document := &httpdoc.Document{}
+
+	r := gin.New()
+	r.Use(Middleware(document, nil))
+	r.GET("/v1/users/:id/books/:bookID", func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"id": c.Param("bookID")})
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/v1/users/1/books/2")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("got %d, want %d", res.StatusCode, http.StatusCreated)
+	}
+	if len(document.Entries) != 1 {
+		t.Fatalf("expect 1 entry: %d", len(document.Entries))
+	}
+	e := document.Entries[0]
+	if want := "/v1/users/:id/books/:bookID"; e.PathTemplate != want {
+		t.Fatalf("got %q, want %q", e.PathTemplate, want)
+	}
+	if want := []httpdoc.Data{{Name: "id", Value: "1"}, {Name: "bookID", Value: "2"}}; !reflect.DeepEqual(e.PathParams, want) {
+		t.Fatalf("got %#v, want %#v", e.PathParams, want)
+	}
+	if e.ResponseStatusCode != http.StatusCreated || !strings.Contains(e.ResponseExample, `{"id":"2"}`) {
+		t.Fatalf("got %d %q", e.ResponseStatusCode, e.ResponseExample)
+	}
+}
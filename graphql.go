@@ -0,0 +1,205 @@
+package httpdoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// GraphQLOperation is the GraphQL operation of the entry. Normally, you don't need to modify this.
+// All fields are exported just for templating.
+type GraphQLOperation struct {
+	// Type is operation type: `query`, `mutation` or `subscription`.
+	Type string
+
+	// Name is operation name. It's empty for anonymous operations.
+	Name string
+
+	// Query is GraphQL document of the request.
+	Query string
+
+	// Errors are errors in the response.
+	Errors []GraphQLError
+}
+
+// GraphQLError is an error in GraphQL response.
+type GraphQLError struct {
+	// Message is the error message.
+	Message string
+
+	// Path is the path of the response field which caused the error, e.g., `user.friends.0`.
+	Path string
+
+	// Code is `extensions.code` of the error (e.g., `NOT_FOUND`) if it's given.
+	Code string
+}
+
+// String returns operation type and name, e.g., `query GetUser`.
+func (o *GraphQLOperation) String() string {
+	if o.Name == "" {
+		return o.Type
+	}
+	return o.Type + " " + o.Name
+}
+
+// graphQLRequest is GraphQL request over HTTP.
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// graphQLResponse is GraphQL response over HTTP.
+type graphQLResponse struct {
+	Data   interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Path       []interface{}          `json:"path"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// graphQLOperation parses GraphQL request and response of the record. It returns the operation,
+// variables and response data. The request is json body, `application/graphql` body or query
+// parameters of GET request. nil is returned if the request is not GraphQL.
+func graphQLOperation(rec *record) (*GraphQLOperation, map[string]interface{}, interface{}) {
+	var req graphQLRequest
+	mediaType, _, _ := mime.ParseMediaType(rec.requestHeaders.Get("Content-Type"))
+	switch {
+	case rec.requestMethod == "GET":
+		req.Query = rec.requestParams.Get("query")
+		req.OperationName = rec.requestParams.Get("operationName")
+		if v := rec.requestParams.Get("variables"); v != "" {
+			json.Unmarshal([]byte(v), &req.Variables)
+		}
+	case mediaType == "application/graphql":
+		req.Query = string(rec.requestBody)
+		req.OperationName = rec.requestParams.Get("operationName")
+	default:
+		if err := json.Unmarshal(rec.requestBody, &req); err != nil {
+			return nil, nil, nil
+		}
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, nil, nil
+	}
+
+	op := &GraphQLOperation{Query: strings.TrimSpace(req.Query)}
+	op.Type, op.Name = graphQLDefinitionOf(req.Query, req.OperationName)
+
+	var res graphQLResponse
+	if err := json.Unmarshal(rec.responseBody, &res); err != nil {
+		return op, req.Variables, nil
+	}
+	for _, e := range res.Errors {
+		path := make([]string, len(e.Path))
+		for i, p := range e.Path {
+			path[i] = fmt.Sprint(p)
+		}
+		graphQLError := GraphQLError{Message: e.Message, Path: strings.Join(path, ".")}
+		if code, ok := e.Extensions["code"]; ok {
+			graphQLError.Code = fmt.Sprint(code)
+		}
+		op.Errors = append(op.Errors, graphQLError)
+	}
+	return op, req.Variables, res.Data
+}
+
+// graphQLDefinition is an operation definition in GraphQL document.
+type graphQLDefinition struct {
+	typ  string
+	name string
+}
+
+// graphQLDefinitionOf returns type and name of the operation in the document. If the document has
+// multiple operations, the operation of the name is picked. The name is used even if it's not in
+// the document. Only top level tokens are read, so selection sets, strings and comments are skipped.
+func graphQLDefinitionOf(query, name string) (string, string) {
+	var (
+		ops          []graphQLDefinition
+		depth, paren int
+		inDefinition bool
+		expectName   bool
+	)
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '#':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(query[i:], `"""`):
+			end := strings.Index(query[i+3:], `"""`)
+			if end < 0 {
+				i = len(query)
+				continue
+			}
+			i += end + 5
+		case c == '"':
+			for i++; i < len(query) && query[i] != '"'; i++ {
+				if query[i] == '\\' {
+					i++
+				}
+			}
+		case c == '(':
+			paren++
+			expectName = false
+		case c == ')':
+			paren--
+		case c == '{' && paren == 0:
+			if depth == 0 && !inDefinition {
+				ops = append(ops, graphQLDefinition{typ: "query"})
+			}
+			depth++
+			expectName = false
+		case c == '}' && paren == 0:
+			depth--
+			if depth == 0 {
+				inDefinition = false
+			}
+		case isGraphQLNameStart(c):
+			start := i
+			for i+1 < len(query) && isGraphQLName(query[i+1]) {
+				i++
+			}
+			if depth > 0 || paren > 0 {
+				continue
+			}
+			switch token := query[start : i+1]; {
+			case expectName:
+				ops[len(ops)-1].name = token
+				expectName = false
+			case inDefinition:
+			case token == "query" || token == "mutation" || token == "subscription":
+				ops = append(ops, graphQLDefinition{typ: token})
+				inDefinition, expectName = true, true
+			case token == "fragment":
+				inDefinition = true
+			}
+		case c != ' ' && c != '\t' && c != '\n' && c != '\r' && c != ',':
+			expectName = false
+		}
+	}
+
+	if len(ops) == 0 {
+		return "query", name
+	}
+	for _, op := range ops {
+		if op.name == name {
+			return op.typ, op.name
+		}
+	}
+	if name == "" {
+		return ops[0].typ, ops[0].name
+	}
+	return ops[0].typ, name
+}
+
+func isGraphQLNameStart(c byte) bool {
+	return c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+func isGraphQLName(c byte) bool {
+	return isGraphQLNameStart(c) || '0' <= c && c <= '9'
+}
@@ -0,0 +1,147 @@
+package httpdoc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGraphQLDefinitionOf(t *testing.T) {
+	cases := []struct {
+		query    string
+		name     string
+		wantType string
+		wantName string
+	}{
+		{`{ user { name } }`, "", "query", ""},
+		{`query GetUser($id: ID!) { user(id: $id) { name } }`, "", "query", "GetUser"},
+		{`mutation { createUser(input: {name: "query Fake"}) { id } }`, "", "mutation", ""},
+		{`subscription OnEvent @live { event { id } }`, "", "subscription", "OnEvent"},
+		{"# query Commented\nquery Real { user { name } }", "", "query", "Real"},
+		{`fragment F on User { name } query A { ...F } mutation B { x }`, "B", "mutation", "B"},
+		{`query A { a } query B { b }`, "", "query", "A"},
+		{`query A { a }`, "Unknown", "query", "Unknown"},
+		{`query ($v: Input = {a: "{"}) { a }`, "", "query", ""},
+		{`"""Description { """ query Described { a }`, "", "query", "Described"},
+	}
+	for _, tc := range cases {
+		typ, name := graphQLDefinitionOf(tc.query, tc.name)
+		if typ != tc.wantType || name != tc.wantName {
+			t.Fatalf("graphQLDefinitionOf(%q, %q): got %q %q, want %q %q", tc.query, tc.name, typ, name, tc.wantType, tc.wantName)
+		}
+	}
+}
+
+func TestRecord_GraphQL(t *testing.T) {
+	doc := &Document{Name: "graphql"}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case bytes.Contains(body, []byte("mutation")):
+			w.Write([]byte(`{"data": null, "errors": [{"message": "user not found", "path": ["deleteUser"], "extensions": {"code": "NOT_FOUND"}}]}`))
+		default:
+			w.Write([]byte(`{"data": {"user": {"name": "tcnksm", "friends": [{"name": "deeeet"}, {"name": "jun06t"}]}}}`))
+		}
+	})
+
+	ts := httptest.NewServer(Record(handler, doc, &RecordOption{
+		GraphQL: true,
+		WithValidate: func(validator *Validator) {
+			if validator.record.requestMethod != "GET" {
+				return
+			}
+			validator.ResponseBody(t, []TestCase{
+				NewTestCase("data.user.name", "tcnksm", "User name"),
+				NewTestCase("data.user.friends[*].name", AnyString(), "Friend names"),
+			}, nil)
+		},
+	}))
+	defer ts.Close()
+
+	query := url.Values{
+		"query":     {`query GetUser($id: ID!) { user(id: $id) { name friends { name } } }`},
+		"variables": {`{"id": "1"}`},
+	}
+	res, err := http.Get(ts.URL + "/graphql?" + query.Encode())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	res, err = http.Post(ts.URL+"/graphql", "application/json", strings.NewReader(`{
+		"query": "mutation DeleteUser($id: ID!) { deleteUser(id: $id) }",
+		"operationName": "DeleteUser",
+		"variables": {"id": "2"}
+	}`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	if got, want := []string{doc.Entries[0].Title(), doc.Entries[1].Title()}, []string{
+		"[200] GET /graphql query GetUser",
+		"[200] POST /graphql mutation DeleteUser",
+	}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	var fields []string
+	for _, f := range doc.Entries[0].ResponseFields {
+		fields = append(fields, f.Name+"|"+f.Type+"|"+f.Description)
+	}
+	if want := []string{
+		"data.user|object|",
+		"data.user.friends|array of object|",
+		"data.user.friends[*].name|string|Friend names",
+		"data.user.name|string|User name",
+	}; !reflect.DeepEqual(fields, want) {
+		t.Fatalf("got %q, want %q", fields, want)
+	}
+	if want := []Data{{Name: "variables.id", Value: "2", Schema: Schema{Type: "string"}}}; !reflect.DeepEqual(doc.Entries[1].RequestFields, want) {
+		t.Fatalf("got %#v, want %#v", doc.Entries[1].RequestFields, want)
+	}
+	if want := []GraphQLError{{Message: "user not found", Path: "deleteUser", Code: "NOT_FOUND"}}; !reflect.DeepEqual(doc.Entries[1].GraphQL.Errors, want) {
+		t.Fatalf("got %#v, want %#v", doc.Entries[1].GraphQL.Errors, want)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.generate(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, want := range []string{
+		"## [200] POST /graphql mutation DeleteUser",
+		"GraphQL mutation\n\n```graphql\nmutation DeleteUser($id: ID!) { deleteUser(id: $id) }\n```",
+		"| user not found | deleteUser | NOT_FOUND |",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expect %q to contain %q", buf.String(), want)
+		}
+	}
+}
+
+func TestRecord_GraphQL_NotGraphQL(t *testing.T) {
+	doc := &Document{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.Write([]byte(`{}`))
+	})
+
+	ts := httptest.NewServer(Record(handler, doc, &RecordOption{GraphQL: true}))
+	defer ts.Close()
+
+	res, err := http.Post(ts.URL+"/graphql", "application/json", strings.NewReader(`{"name": "tcnksm"}`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	if e := doc.Entries[0]; e.GraphQL != nil || e.Operation != "" {
+		t.Fatalf("expect not to be recorded as GraphQL: %#v", e.GraphQL)
+	}
+}
@@ -0,0 +1,406 @@
+package httpdoc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// harVersion is HTTP Archive format version WriteHAR emits.
+const harVersion = "1.2"
+
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+
+	// WebSocketMessages is a custom field which browser devtools use to store WebSocket messages.
+	WebSocketMessages []harWebSocketMessage `json:"_webSocketMessages,omitempty"`
+}
+
+type harRequest struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []harCookie  `json:"cookies"`
+	Headers     []harPair    `json:"headers"`
+	QueryString []harPair    `json:"queryString"`
+	PostData    *harPostData `json:"postData,omitempty"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []harCookie `json:"cookies"`
+	Headers     []harPair   `json:"headers"`
+	Content     harContent  `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type harPair struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+type harPostData struct {
+	MimeType string    `json:"mimeType"`
+	Params   []harPair `json:"params,omitempty"`
+	Text     string    `json:"text"`
+
+	// Encoding is not in HAR 1.2 spec for post data, but it's set like content to tell base64
+	// encoded binary body.
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harWebSocketMessage struct {
+	Type   string  `json:"type"`
+	Time   float64 `json:"time"`
+	Opcode int     `json:"opcode"`
+	Data   string  `json:"data"`
+}
+
+// harTimings is timings of the entry in milliseconds. -1 means the timing is not applicable.
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// WriteHAR writes recorded entries in HTTP Archive (HAR) 1.2 format. Unlike markdown documentation,
+// it contains all request & response headers (`ExcludeHeaders` is not applied), cookies, query string,
+// body and timings which Record middleware captured. Binary body (e.g., protocol buffer) is base64 encoded.
+// Values of secret headers, cookies and parameters (see Document.Secrets) are replaced with placeholders
+// unless Document.HARRaw is true.
+//
+// HAR can be imported by browser devtools, load testing tools or proxies.
+func (d *Document) WriteHAR(w io.Writer) error {
+	har := harFile{
+		Log: harLog{
+			Version: harVersion,
+			Creator: harCreator{
+				Name: "go-httpdoc",
+			},
+			Entries: make([]harEntry, 0, len(d.Entries)),
+		},
+	}
+
+	for _, e := range d.Entries {
+		har.Log.Entries = append(har.Log.Entries, d.harEntry(&e))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(&har)
+}
+
+func (d *Document) harEntry(e *Entry) harEntry {
+	// If entry is not recorded by Record middleware, build values from documentation.
+	rec := e.record
+	if rec == nil {
+		params := make(url.Values)
+		for _, p := range e.RequestParams {
+			params.Add(p.Name, fmt.Sprint(p.Value))
+		}
+		requestURL := d.baseURL() + e.Path
+		if len(params) > 0 {
+			requestURL += "?" + params.Encode()
+		}
+
+		rec = &record{
+			requestMethod:  e.Method,
+			requestURL:     requestURL,
+			requestProto:   "HTTP/1.1",
+			requestPath:    e.Path,
+			requestParams:  params,
+			requestHeaders: dataToHeader(e.RequestHeaders),
+			requestBody:    []byte(e.RequestExample),
+
+			responseStatusCode: e.ResponseStatusCode,
+			responseHeaders:    dataToHeader(e.ResponseHeaders),
+			responseBody:       []byte(e.ResponseExample),
+		}
+	}
+
+	if !d.HARRaw {
+		rec = d.harRedact(rec)
+	}
+
+	status := rec.responseStatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	// Host header is removed from request headers by net/http server, so restore it from URL.
+	requestHeaders := harHeaders(rec.requestHeaders)
+	if u, err := url.Parse(rec.requestURL); err == nil && rec.requestHeaders.Get("Host") == "" {
+		requestHeaders = append([]harPair{{Name: "Host", Value: u.Host}}, requestHeaders...)
+	}
+
+	entry := harEntry{
+		StartedDateTime: rec.startedAt.Format(time.RFC3339Nano),
+		Time:            milliseconds(e.Duration),
+		Request: harRequest{
+			Method:      rec.requestMethod,
+			URL:         rec.requestURL,
+			HTTPVersion: rec.requestProto,
+			Cookies:     harRequestCookies(rec.requestHeaders),
+			Headers:     requestHeaders,
+			QueryString: harQueryString(rec.requestParams),
+			HeadersSize: -1,
+			BodySize:    len(rec.requestBody),
+		},
+		Response: harResponse{
+			Status:      status,
+			StatusText:  http.StatusText(status),
+			HTTPVersion: rec.requestProto,
+			Cookies:     harResponseCookies(rec.responseHeaders),
+			Headers:     harHeaders(rec.responseHeaders),
+			Content:     harBodyContent(rec.responseHeaders.Get("Content-Type"), rec.responseBody),
+			RedirectURL: rec.responseHeaders.Get("Location"),
+			HeadersSize: -1,
+			BodySize:    len(rec.responseBody),
+		},
+		Timings: harTimings{
+			Blocked: -1,
+			DNS:     -1,
+			Connect: -1,
+			SSL:     -1,
+			Wait:    milliseconds(e.TimeToFirstByte),
+			Receive: milliseconds(e.Duration - e.TimeToFirstByte),
+		},
+		Comment: e.Description,
+	}
+
+	if len(rec.requestBody) > 0 {
+		mimeType := rec.requestHeaders.Get("Content-Type")
+		content := harBodyContent(mimeType, rec.requestBody)
+		postData := &harPostData{
+			MimeType: mimeType,
+			Text:     content.Text,
+			Encoding: content.Encoding,
+		}
+		if mimeType == "application/x-www-form-urlencoded" {
+			if form, err := url.ParseQuery(string(rec.requestBody)); err == nil {
+				postData.Params = harQueryString(form)
+			}
+		}
+		entry.Request.PostData = postData
+	}
+
+	if e.WebSocket != nil {
+		for _, m := range e.WebSocket.Messages {
+			message := harWebSocketMessage{
+				Type:   "receive",
+				Time:   float64(rec.startedAt.Add(m.Offset).UnixNano()) / float64(time.Second),
+				Opcode: m.Opcode,
+				Data:   harBodyContent("", m.data).Text,
+			}
+			if m.Direction == WebSocketClientToServer {
+				message.Type = "send"
+			}
+			entry.WebSocketMessages = append(entry.WebSocketMessages, message)
+		}
+	}
+
+	return entry
+}
+
+// harRedact returns copy of the record whose values of secret request headers, cookies, parameters
+// and response headers are replaced with placeholders.
+func (d *Document) harRedact(rec *record) *record {
+	redacted := *rec
+	redacted.requestHeaders = d.harRedactHeader(rec.requestHeaders)
+	redacted.responseHeaders = d.harRedactHeader(rec.responseHeaders)
+
+	var secret bool
+	redacted.requestParams = make(url.Values, len(rec.requestParams))
+	for name, values := range rec.requestParams {
+		for _, v := range values {
+			if d.isSecret(name) {
+				v, secret = redactSecret(name, v), true
+			}
+			redacted.requestParams.Add(name, v)
+		}
+	}
+	if u, err := url.Parse(rec.requestURL); err == nil && secret {
+		u.RawQuery = redacted.requestParams.Encode()
+		redacted.requestURL = u.String()
+	}
+	return &redacted
+}
+
+// harRedactHeader returns copy of the header whose secret values are replaced with placeholders.
+// Names of cookies in `Cookie` and `Set-Cookie` headers are kept and their values are replaced, e.g.,
+// `session={session}`.
+func (d *Document) harRedactHeader(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for name, values := range header {
+		for _, v := range values {
+			switch {
+			case !d.isSecret(name):
+			case http.CanonicalHeaderKey(name) == "Cookie":
+				r := http.Request{Header: http.Header{"Cookie": {v}}}
+				for _, c := range r.Cookies() {
+					v = redactCookie(v, c.Name, "{"+variableName(c.Name)+"}")
+				}
+			case http.CanonicalHeaderKey(name) == "Set-Cookie":
+				v = redactSetCookie(v)
+			default:
+				v = redactSecret(name, v)
+			}
+			redacted[name] = append(redacted[name], v)
+		}
+	}
+	return redacted
+}
+
+// redactSetCookie replaces the cookie value in `Set-Cookie` header. The name and attributes (e.g.,
+// `Path=/`) are kept.
+func redactSetCookie(header string) string {
+	parts := strings.SplitN(header, ";", 2)
+	kv := strings.SplitN(parts[0], "=", 2)
+	if len(kv) < 2 {
+		return header
+	}
+	name := strings.TrimSpace(kv[0])
+	parts[0] = name + "={" + variableName(name) + "}"
+	return strings.Join(parts, ";")
+}
+
+// harHeaders converts headers to HAR format. Headers are sorted by name to keep the output stable.
+func harHeaders(header http.Header) []harPair {
+	pairs := make([]harPair, 0, len(header))
+	for _, name := range sortedHeaderNames(header) {
+		for _, v := range header[name] {
+			pairs = append(pairs, harPair{Name: name, Value: v})
+		}
+	}
+	return pairs
+}
+
+func harQueryString(values url.Values) []harPair {
+	pairs := make([]harPair, 0, len(values))
+	for _, name := range sortedHeaderNames(http.Header(values)) {
+		for _, v := range values[name] {
+			pairs = append(pairs, harPair{Name: name, Value: v})
+		}
+	}
+	return pairs
+}
+
+func harRequestCookies(header http.Header) []harCookie {
+	r := http.Request{Header: header}
+	cookies := make([]harCookie, 0)
+	for _, c := range r.Cookies() {
+		cookies = append(cookies, harCookie{Name: c.Name, Value: c.Value})
+	}
+	return cookies
+}
+
+func harResponseCookies(header http.Header) []harCookie {
+	r := http.Response{Header: header}
+	cookies := make([]harCookie, 0)
+	for _, c := range r.Cookies() {
+		cookie := harCookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Path:     c.Path,
+			Domain:   c.Domain,
+			HTTPOnly: c.HttpOnly,
+			Secure:   c.Secure,
+		}
+		if !c.Expires.IsZero() {
+			cookie.Expires = c.Expires.Format(time.RFC3339)
+		}
+		cookies = append(cookies, cookie)
+	}
+	return cookies
+}
+
+// harBodyContent converts body to HAR content. If body is not valid UTF-8 text, it's base64 encoded.
+func harBodyContent(mimeType string, body []byte) harContent {
+	content := harContent{
+		Size:     len(body),
+		MimeType: mimeType,
+	}
+	if utf8.Valid(body) {
+		content.Text = string(body)
+	} else {
+		content.Text = base64.StdEncoding.EncodeToString(body)
+		content.Encoding = "base64"
+	}
+	return content
+}
+
+func sortedHeaderNames(header http.Header) []string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func dataToHeader(data []Data) http.Header {
+	header := make(http.Header)
+	for _, d := range data {
+		header.Add(d.Name, fmt.Sprint(d.Value))
+	}
+	return header
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
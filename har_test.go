@@ -0,0 +1,216 @@
+package httpdoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDocument_WriteHAR(t *testing.T) {
+	document := &Document{
+		ExcludeHeaders: testExcludeHeaders,
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/v1/hello", Record(http.HandlerFunc(testHandler), document, nil))
+	mux.Handle("/v1/hello_proto", Record(http.HandlerFunc(testHandlerProto), document, nil))
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	req, err := http.NewRequest("POST", testServer.URL+"/v1/hello?token=123456", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Cookie", "session=abc")
+	req.Header.Set("Authorization", "Bearer secret")
+	if _, err := http.DefaultClient.Do(req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := http.Get(testServer.URL + "/v1/hello_proto"); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := document.WriteHAR(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got harFile
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Log.Version != harVersion {
+		t.Fatalf("got %q, want %q", got.Log.Version, harVersion)
+	}
+	if got, want := len(got.Log.Entries), 2; got != want {
+		t.Fatalf("got %d entries, want %d", got, want)
+	}
+
+	// Secret values are replaced with placeholders.
+	entry := got.Log.Entries[0]
+	if got, want := entry.Request.URL, testServer.URL+"/v1/hello?token=%7Btoken%7D"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := entry.Request.QueryString, []harPair{{Name: "token", Value: "{token}"}}; len(got) != 1 || got[0] != want[0] {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got, want := entry.Request.Cookies, []harCookie{{Name: "session", Value: "{session}"}}; len(got) != 1 || got[0] != want[0] {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for _, h := range entry.Request.Headers {
+		if h.Name == "Authorization" && h.Value != "Bearer {token}" {
+			t.Fatalf("got %q, want %q", h.Value, "Bearer {token}")
+		}
+	}
+	if strings.Contains(buf.String(), "123456") || strings.Contains(buf.String(), "abc") || strings.Contains(buf.String(), "Bearer secret") {
+		t.Fatalf("expect secrets not to be written: %s", buf.String())
+	}
+	if entry.Request.PostData == nil || entry.Request.PostData.Text != "hello" {
+		t.Fatalf("expect post data to be recorded: %v", entry.Request.PostData)
+	}
+
+	// Excluded headers are included in HAR.
+	var userAgent, host bool
+	for _, h := range entry.Request.Headers {
+		userAgent = userAgent || h.Name == "User-Agent"
+		host = host || h.Name == "Host"
+	}
+	if !userAgent || !host {
+		t.Fatalf("expect all request headers to be included: %v", entry.Request.Headers)
+	}
+
+	if got, want := entry.Response.Content.Text, "hello"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if entry.StartedDateTime == "" || entry.Time < 0 {
+		t.Fatalf("expect timings to be recorded: %q %v", entry.StartedDateTime, entry.Time)
+	}
+
+	entry = got.Log.Entries[1]
+	if got, want := entry.Response.Content.Encoding, "base64"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestDocument_WriteHAR_NotRecorded(t *testing.T) {
+	document := &Document{
+		Entries: testExportEntries,
+	}
+
+	var buf bytes.Buffer
+	if err := document.WriteHAR(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got harFile
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := got.Log.Entries[0]
+	if got, want := entry.Request.URL, defaultBaseURL+"/v1/user?pretty=true&token=%7Btoken%7D"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := entry.Response.Content.Text, `{"id": 1, "name": "tcnksm"}`; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestDocument_WriteHAR_Raw(t *testing.T) {
+	document := &Document{HARRaw: true}
+	handler := Record(http.HandlerFunc(testHandlerProto), document, nil)
+	testServer := httptest.NewServer(handler)
+	defer testServer.Close()
+
+	req, err := http.NewRequest("POST", testServer.URL+"/v1/hello?token=123456", bytes.NewReader([]byte{0xff, 0x00}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/protobuf")
+	req.Header.Set("Cookie", "session=abc")
+	if _, err := http.DefaultClient.Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := document.WriteHAR(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got harFile
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := got.Log.Entries[0]
+	if got, want := entry.Request.URL, testServer.URL+"/v1/hello?token=123456"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := entry.Request.Cookies, []harCookie{{Name: "session", Value: "abc"}}; len(got) != 1 || got[0] != want[0] {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// Binary post data is base64 encoded with the marker.
+	if got, want := *entry.Request.PostData, (harPostData{MimeType: "application/protobuf", Text: "/wA=", Encoding: "base64"}); got.Text != want.Text || got.Encoding != want.Encoding {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestDocument_WriteHAR_SetCookie(t *testing.T) {
+	document := &Document{}
+	handler := Record(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/", HttpOnly: true})
+		w.Write([]byte("hello"))
+	}), document, nil)
+	testServer := httptest.NewServer(handler)
+	defer testServer.Close()
+
+	if _, err := http.Get(testServer.URL + "/v1/login"); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := document.WriteHAR(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got harFile
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the value is replaced, the name and attributes are kept.
+	response := got.Log.Entries[0].Response
+	var setCookie string
+	for _, h := range response.Headers {
+		if h.Name == "Set-Cookie" {
+			setCookie = h.Value
+		}
+	}
+	if want := "session={session}; Path=/; HttpOnly"; setCookie != want {
+		t.Fatalf("got %q, want %q", setCookie, want)
+	}
+	if got, want := response.Cookies, []harCookie{{Name: "session", Value: "{session}", Path: "/", HTTPOnly: true}}; len(got) != 1 || got[0] != want[0] {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if strings.Contains(buf.String(), "abc") {
+		t.Fatalf("expect cookie value not to be written: %s", buf.String())
+	}
+}
+
+func TestRedactSetCookie(t *testing.T) {
+	cases := map[string]string{
+		"session=abc":                 "session={session}",
+		"session=abc; Path=/; Secure": "session={session}; Path=/; Secure",
+		"invalid":                     "invalid",
+	}
+	for header, want := range cases {
+		if got := redactSetCookie(header); got != want {
+			t.Fatalf("%q: got %q, want %q", header, got, want)
+		}
+	}
+}
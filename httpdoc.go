@@ -9,13 +9,17 @@ import (
 	"net/http"
 	"os"
 	"sort"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
 const (
 	// EnvHTTPDoc is the environmental variable that determines if Generate func generates documentation
 	// to the given file or not. By default, it does not generate. If this variable is not empty, then it does.
+	// It's also used to update snapshots (see SnapshotUpdate).
 	EnvHTTPDoc = "HTTPDOC"
 )
 
@@ -29,6 +33,58 @@ type Document struct {
 	// If you want to exclude header only in specific endpoint, then use `RecordOption.ExcludeHeaders`.
 	ExcludeHeaders []string
 
+	// BaseURL is base URL of the API (e.g., `https://api.example.com`). This is used for exported
+	// requests like Postman collection. By default, `http://localhost` is used.
+	BaseURL string
+
+	// Secrets is list of header or parameter names whose values are secret. In exported requests,
+	// these values are replaced with variables and in documentation, they are replaced with
+	// placeholders (e.g., `{token}`). Well-known names like `Authorization` are always treated as secret.
+	Secrets []string
+
+	// HARRaw option, WriteHAR writes values of secret headers, cookies and parameters as they are.
+	// By default, they are replaced with placeholders like documentation.
+	HARRaw bool
+
+	// SecuritySchemes is list of schemes which endpoints use to authenticate. Bearer token and basic
+	// authentication are detected from recorded `Authorization` header and added automatically.
+	// API keys must be declared here to be detected.
+	SecuritySchemes []SecurityScheme
+
+	// ProtoFiles is proto file descriptors used to document fields of protocol buffer messages
+	// (see RecordOption.WithProtoBuffer). By default, descriptors registered by generated code
+	// (protoregistry.GlobalFiles) are used but they don't have comments. To use comments in .proto
+	// files as descriptions, load FileDescriptorSet by LoadFileDescriptorSet.
+	ProtoFiles *protoregistry.Files
+
+	// ProtoSourceURL is URL template of .proto files to link endpoints to RPC definitions (see
+	// RecordOption.RPCAdapters), e.g., `https://github.com/org/repo/blob/master/proto/{file}#L{line}`.
+	// `{file}` is replaced with the path of .proto file and `{line}` is replaced with the line of
+	// the method. Line is 1 if the file doesn't have source info.
+	ProtoSourceURL string
+
+	// SortBy is list of functions to sort entries in documentation (e.g., `[]LessFunc{ByPath, ByMethod, ByStatus}`).
+	// If entries are equal by the first function, the next one is used. By default, entries are written in
+	// the recorded order. Identical entries are written only once.
+	SortBy []LessFunc
+
+	// SplitByTag option, Document.GenerateDir writes one file per tag instead of per endpoint.
+	SplitByTag bool
+
+	// ShowStats option, documentation includes performance statistics of each endpoint.
+	// See Document.Stats.
+	ShowStats bool
+
+	// ShowCoverage option, documentation includes documentation coverage. See Document.Coverage.
+	ShowCoverage bool
+
+	// Routes is list of routes which the service registers (e.g., `GET /v1/users/{id}` or `/v1/users`)
+	// in the pattern syntax of RecordAll. It's used to report routes never exercised by tests (see
+	// Document.Coverage). Only patterns of RecordAll options which don't end with a slash are added
+	// automatically. Routes of the router are not discovered, neither by RecordAll nor by router
+	// adapters (e.g., chidoc), so set other routes by hand to report them.
+	Routes []string
+
 	// Entries stores all recorded results by Record middleware. Normally, you don't need to modify this.
 	// This is exported just for templating.
 	Entries []Entry
@@ -42,15 +98,45 @@ type Document struct {
 // Entry is recorded results by Record middleware. Normally, you don't need to modify this.
 // All fields are exported just for templating.
 type Entry struct {
+	// Summary is short summary of endpoint.
+	Summary string
+
 	// Description is description of endpoint.
 	Description string
 
+	// Tags is list of tags to group endpoints.
+	Tags []string
+
+	// OperationID is unique identifier of endpoint (e.g., `getUser`).
+	OperationID string
+
+	// Deprecated is true if endpoint is deprecated. Replacement is endpoint to use instead.
+	Deprecated  bool
+	Replacement string
+
+	// Since is API version when endpoint is introduced.
+	Since string
+
+	// Security is security schemes which endpoint requires.
+	Security []SecurityRequirement
+
 	// Method is HTTP method.
 	Method string
 
 	// Path is request path.
 	Path string
 
+	// PathTemplate is the path pattern of the route which handled the request (e.g., `/v1/users/{id}`),
+	// RouteName is the name of the route and PathParams are path parameters. They are set when
+	// RecordOption.RouteResolver resolves the route. Entries are grouped by PathTemplate instead of Path.
+	PathTemplate string
+	RouteName    string
+	PathParams   []Data
+
+	// Operation is the operation of the endpoint which shares the path with other operations, e.g.,
+	// `query GetUser` of GraphQL endpoint or the method of JSON-RPC endpoint. Entries are grouped by method, path and operation.
+	Operation string
+
 	RequestParams  []Data
 	RequestHeaders []Data
 	RequestFields  []Data
@@ -68,13 +154,86 @@ type Entry struct {
 	// it uses it here without modification. If you use protocol buffer format for your response body
 	// it unmarshals it in the given struct and encodes it into json format.
 	ResponseExample string
+
+	// ResponseEvents is events of streaming response, e.g., Server-Sent Events or newline-delimited JSON.
+	// It's empty if response is not streaming.
+	ResponseEvents []Event
+
+	// RPC is the proto RPC which the endpoint is mapped to by grpc-gateway, Connect or Twirp.
+	// It's nil if the endpoint is not RPC (see RecordOption.RPCAdapters).
+	RPC *RPC
+
+	// GraphQL is the GraphQL operation of the request. It's nil if the endpoint is not recorded
+	// as GraphQL endpoint (see RecordOption.GraphQL).
+	GraphQL *GraphQLOperation
+
+	// JSONRPC is the JSON-RPC call of the request. It's nil if the endpoint is not recorded as
+	// JSON-RPC endpoint (see RecordOption.JSONRPC).
+	JSONRPC *JSONRPCCall
+
+	// WebSocket is messages exchanged after the connection is upgraded to WebSocket.
+	// It's nil if the endpoint is not WebSocket.
+	WebSocket *WebSocketSession
+
+	// Duration is time which handler took to respond.
+	Duration time.Duration
+
+	// TimeToFirstByte is time from handler is called until handler writes the first byte of response
+	// (status code or body).
+	TimeToFirstByte time.Duration
+
+	// RequestSize is request body size in bytes.
+	RequestSize int
+
+	// ResponseSize is response body size in bytes.
+	ResponseSize int
+
+	// Anchor is the anchor of the entry heading in documentation. It's set when documentation is generated.
+	Anchor string
+
+	// record is raw request & response values. It's used for replaying recorded responses.
+	record *record
+
+	// id is the position of the entry in documentView.
+	id int
+
+	// validated is labels of fields which are validated by Validator. It's used for coverage.
+	validated []string
+
+	// requestDecoded is true if RequestExample is json decoded from protocol buffer encoded body.
+	// It's used to send the example as json in exported requests and snippets.
+	requestDecoded bool
 }
 
 // RecordOption is option for Record middleware.
 type RecordOption struct {
+	// Summary is short summary of endpoint. This is used for Entry.Summary.
+	Summary string
+
 	// Description is description of endpoint. This is used for Entry.Description.
 	Description string
 
+	// Tags is list of tags of endpoint (e.g., `user`). In documentation, table of contents
+	// is grouped by tags.
+	Tags []string
+
+	// OperationID is unique identifier of endpoint (e.g., `getUser`). It's also used by
+	// Validator.ConformsTo when operationId is not given.
+	OperationID string
+
+	// Deprecated option, endpoint is documented as deprecated. If Replacement is provided
+	// (e.g., `GET /v2/user`), documentation tells to use it instead.
+	Deprecated  bool
+	Replacement string
+
+	// Since is API version when endpoint is introduced (e.g., `v1.2.0`).
+	Since string
+
+	// Security is security schemes which endpoint requires. If nil, it's detected from the request
+	// (see Document.SecuritySchemes). Use empty slice for endpoint which does not require authentication.
+	// In documentation, credential values of the schemes are replaced with placeholders.
+	Security []SecurityRequirement
+
 	// ExcludeHeaders is list of headers to exclude from documentation.
 	// This is applied only one entry (endpoint). If you want to exclude header in all endpoints
 	// use `Document.ExcludeHeaders`.
@@ -90,8 +249,38 @@ type RecordOption struct {
 	// See more usage in Validator methods.
 	WithValidate func(*Validator)
 
-	// WithProtoBuffer option is used for protocol buffer request & response.
+	// WithProtoBuffer option is used for protocol buffer request & response. Only bodies whose
+	// Content-Type is protocol buffer (e.g., `application/protobuf`, `application/x-protobuf` or
+	// `application/octet-stream`) or empty are decoded as protocol buffer. Json bodies are treated
+	// as json, so the same endpoint can be recorded in both formats, and other bodies (e.g., text
+	// error messages) are written as they are.
 	WithProtoBuffer *ProtoBufferOption
+
+	// RPCAdapters is list of adapters to map requests to proto RPCs (e.g., `[]RPCAdapter{GRPCGatewayAdapter()}`).
+	// If a request is mapped, the RPC is documented and bodies are decoded as input and output messages
+	// of the method unless WithProtoBuffer has them. Services are looked up in Document.ProtoFiles or
+	// registered files. Leading comments of the method are used as description if it's not given.
+	RPCAdapters []RPCAdapter
+
+	// RouteResolver resolves the route of the router which handled the request. It's used to
+	// document the path template, route name and path parameters. Normally, it's set by router
+	// adapters (e.g., chidoc and muxdoc packages).
+	RouteResolver RouteResolver
+
+	// GraphQL option, the endpoint is recorded as GraphQL endpoint. The request is parsed and entries
+	// are keyed by operation type and name (e.g., `query GetUser`). Variables are documented as
+	// `variables.*` request fields and response data is documented as `data.*` response fields.
+	// Errors in the response are also documented. To validate fields, use nil with Validator.RequestBody
+	// or Validator.ResponseBody.
+	GraphQL bool
+
+	// JSONRPC option, the endpoint is recorded as JSON-RPC 2.0 endpoint. Batch requests are split
+	// into calls and each call is recorded as an entry keyed by the method. Params and result are
+	// documented as `params.*` request fields and `result.*` response fields, and error object is
+	// documented with its code. WithValidate is called for each call with the request and response
+	// objects of the call, so use nil with Validator.RequestBody or Validator.ResponseBody to
+	// validate `params.*` and `result.*` fields. Validator.JSONRPC returns the call to validate.
+	JSONRPC bool
 }
 
 // ProtoBufferOption is option for protocol buffer.
@@ -103,6 +292,18 @@ type ProtoBufferOption struct {
 	// ResponseUnmarshaler is used to unmarshal protocol buffer encoded response body.
 	// This is used for generating human readable response example (json format).
 	ResponseUnmarshaler proto.Unmarshaler
+
+	// RequestMessage and ResponseMessage return new messages to unmarshal request and response bodies.
+	// Unlike unmarshalers which are reused, new message is created for every request. They are
+	// preferred to unmarshalers and message types.
+	RequestMessage  func() proto.Message
+	ResponseMessage func() proto.Message
+
+	// RequestType and ResponseType are full names of request and response messages (e.g.,
+	// `example.v1.User`). Messages are resolved from registered types or Document.ProtoFiles.
+	// New message is created for every request.
+	RequestType  string
+	ResponseType string
 }
 
 // Data represents a request or response parameter value. Normally, you don't need to modify this.
@@ -116,6 +317,12 @@ type Data struct {
 
 	// Description is description for this data. You can provide this via a validator.
 	Description string
+
+	// Schema is type and restrictions of request and response fields.
+	Schema
+
+	// explicit is TestCase.Schema. It's kept to override the schema generated from the body.
+	explicit *Schema
 }
 
 type byName []Data
@@ -148,99 +355,272 @@ func Record(next http.Handler, document *Document, opt *RecordOption) http.Handl
 		var requestBody bytes.Buffer
 		r.Body = ioutil.NopCloser(io.TeeReader(r.Body, &requestBody))
 
+		startedAt := time.Now()
 		next.ServeHTTP(&rw, r)
+		duration := time.Since(startedAt)
 
-		// If protobuffer option is provided, use protoUnmarshalFunc for
-		// validator, by default, use json unmashal func.
-		unmarshalFunc := defaultUnmarshalFunc
-		if opt.WithProtoBuffer != nil {
-			unmarshalFunc = protoUnmarshalFunc
+		var route Route
+		if opt.RouteResolver != nil {
+			if resolved := opt.RouteResolver(r); resolved != nil {
+				route = *resolved
+			}
 		}
 
-		validator := &Validator{
-			record: &record{
-				requestParams:  r.URL.Query(),
-				requestHeaders: r.Header,
-				requestBody:    requestBody.Bytes(),
-
-				responseStatusCode: rw.statusCode,
-				responseHeaders:    rw.Header(),
-				responseBody:       rw.responseBody,
-			},
-			unmarshalFunc: unmarshalFunc,
-			assertFunc:    defaultAssertFunc,
+		// If the request is mapped to RPC, bodies are decoded as messages of the method.
+		protoOpt := opt.WithProtoBuffer
+		rpc := document.resolveRPC(r, opt.RPCAdapters)
+		if rpc != nil {
+			protoOpt = rpc.protoBufferOption(protoOpt)
 		}
 
-		if opt.WithValidate != nil {
-			opt.WithValidate(validator)
+		requestMessage, responseMessage, err := document.protoMessages(protoOpt)
+		if err != nil {
+			document.logger.Printf("[WARN] httpdoc: %s", err)
 		}
 
-		excludeHeaders := append(opt.ExcludeHeaders, document.ExcludeHeaders...)
+		// If handler upgraded connection to WebSocket, handshake response is written on the hijacked
+		// connection instead of the responseWriter.
+		responseHeader := rw.Header()
+		var webSocket *WebSocketSession
+		if rw.hijacked != nil {
+			if res, session := rw.hijacked.webSocketSession(r, startedAt, requestMessage, responseMessage); session != nil {
+				rw.statusCode = res.StatusCode
+				rw.firstByteAt = rw.hijacked.sentWrites[0].at
+				responseHeader = res.Header
+				webSocket = session
+			}
+		}
 
-		requestParams := mergeData(validator.requestParams, convertHeaders(r.URL.Query()))
+		timeToFirstByte := duration
+		if !rw.firstByteAt.IsZero() {
+			timeToFirstByte = rw.firstByteAt.Sub(startedAt)
+		}
 
-		requestHeaders := mergeData(validator.requestHeaders, convertHeaders(r.Header))
-		requestHeaders = excludeData(requestHeaders, excludeHeaders)
+		// If protobuffer option is provided, use protoUnmarshalFunc for
+		// validator, by default, use json unmashal func. Even if protobuffer option is provided,
+		// json encoded body is unmarshaled as json (see isProtoContentType).
+		requestUnmarshalFunc, responseUnmarshalFunc := defaultUnmarshalFunc, defaultUnmarshalFunc
+		requestProto := isProtoContentType(r.Header.Get("Content-Type"))
+		responseProto := isProtoContentType(responseHeader.Get("Content-Type"))
+		if protoOpt != nil {
+			requestUnmarshalFunc, responseUnmarshalFunc = protoJSONUnmarshalFunc, protoJSONUnmarshalFunc
+			if requestProto {
+				requestUnmarshalFunc = protoUnmarshalFunc
+			}
+			if responseProto {
+				responseUnmarshalFunc = protoUnmarshalFunc
+			}
+		}
 
-		responseHeaders := mergeData(validator.responseHeaders, convertHeaders(rw.Header()))
-		responseHeaders = excludeData(responseHeaders, excludeHeaders)
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
 
-		requestExample := requestBody.String()
-		responseExample := string(rw.responseBody)
-		if opt.WithProtoBuffer != nil {
-			// FIXME(tcnksm): Want to use jsonpb but sometimes panic happens while marshalling....
-			if unmarshaler := opt.WithProtoBuffer.RequestUnmarshaler; unmarshaler != nil {
-				unmarshaler.Unmarshal(requestBody.Bytes())
+		rec := &record{
+			requestMethod:  r.Method,
+			requestURL:     scheme + "://" + r.Host + r.URL.RequestURI(),
+			requestProto:   r.Proto,
+			requestPath:    r.URL.Path,
+			pathParams:     route.Params,
+			requestParams:  r.URL.Query(),
+			requestHeaders: r.Header,
+			requestBody:    requestBody.Bytes(),
+
+			responseStatusCode: rw.statusCode,
+			responseHeaders:    responseHeader,
+			responseBody:       rw.responseBody,
+			responseEvents:     parseEvents(responseHeader.Get("Content-Type"), rw.responseBody, rw.writes, startedAt),
+
+			startedAt: startedAt,
+		}
+
+		// JSON-RPC request is split into calls and each call is recorded as an entry.
+		exchanges := []jsonRPCExchange{{record: rec}}
+		if opt.JSONRPC {
+			if calls := jsonRPCExchanges(rec); len(calls) > 0 {
+				exchanges = calls
+			}
+		}
+
+		for _, x := range exchanges {
+			rec := x.record
+
+			validator := &Validator{
+				record:      rec,
+				operationID: opt.OperationID,
+				assertFunc:  defaultAssertFunc,
 
-				var buf bytes.Buffer
-				encoder := json.NewEncoder(&buf)
-				encoder.Encode(unmarshaler)
-				s := buf.String()
-				buf.Reset()
-				json.Indent(&buf, []byte(s), "", "  ")
+				requestUnmarshalFunc:  requestUnmarshalFunc,
+				responseUnmarshalFunc: responseUnmarshalFunc,
+				requestMessage:        requestMessage.new,
+				responseMessage:       responseMessage.new,
 
-				requestExample = buf.String()
+				jsonRPC: x.call,
 			}
 
-			if unmarshaler := opt.WithProtoBuffer.ResponseUnmarshaler; unmarshaler != nil {
-				unmarshaler.Unmarshal(rw.responseBody)
+			if opt.WithValidate != nil {
+				opt.WithValidate(validator)
+			}
+
+			excludeHeaders := append(opt.ExcludeHeaders, document.ExcludeHeaders...)
+
+			pathParams := mergeParams(validator.pathParams, route.Params)
+			requestParams := mergeData(validator.requestParams, convertHeaders(r.URL.Query()))
 
-				var buf bytes.Buffer
-				encoder := json.NewEncoder(&buf)
-				encoder.Encode(unmarshaler)
-				s := buf.String()
-				buf.Reset()
-				json.Indent(&buf, []byte(s), "", "  ")
+			requestHeaders := mergeData(validator.requestHeaders, convertHeaders(r.Header))
+			requestHeaders = excludeData(requestHeaders, excludeHeaders)
 
-				responseExample = buf.String()
+			security := opt.Security
+			if security == nil {
+				security = document.detectSecurity(r)
+			}
+			document.redactSecurity(security, requestHeaders, requestParams)
+
+			responseHeaders := mergeData(validator.responseHeaders, convertHeaders(responseHeader))
+			responseHeaders = excludeData(responseHeaders, excludeHeaders)
+
+			requestExample := string(rec.requestBody)
+			responseExample := string(rec.responseBody)
+			requestFields := validator.requestFields
+			responseFields := validator.responseFields
+			requestDecoded := false
+			if protoOpt != nil {
+				// FIXME(tcnksm): Want to use jsonpb but sometimes panic happens while marshalling....
+				requestExample, requestFields, requestDecoded = document.protoBody(requestMessage, requestProto, rec.requestBody, requestExample, requestFields)
+				responseExample, responseFields, _ = document.protoBody(responseMessage, responseProto, rec.responseBody, responseExample, responseFields)
 			}
-		}
 
-		entry := Entry{
-			Description: opt.Description,
+			var (
+				operation string
+				graphQL   *GraphQLOperation
+				jsonRPC   *JSONRPCCall
+			)
+			if opt.GraphQL {
+				var (
+					variables map[string]interface{}
+					data      interface{}
+				)
+				if graphQL, variables, data = graphQLOperation(rec); graphQL != nil {
+					operation = graphQL.String()
+					requestFields = mergeFields(requestFields, jsonFields("variables", variables))
+					responseFields = mergeFields(responseFields, jsonFields("data", data))
+				}
+			}
 
-			Method: r.Method,
-			Path:   r.URL.Path,
+			if x.call != nil {
+				jsonRPC = x.call
+				operation = jsonRPC.Method
+				requestFields = mergeFields(requestFields, jsonFields("params", x.params))
+				responseFields = mergeFields(responseFields, jsonFields("result", x.result))
+			}
 
-			RequestHeaders: requestHeaders,
-			RequestParams:  requestParams,
-			RequestFields:  validator.requestFields,
-			RequestExample: requestExample,
+			description := opt.Description
+			if description == "" && rpc != nil {
+				description = rpc.description
+			}
 
-			ResponseStatusCode: rw.statusCode,
-			ResponseHeaders:    responseHeaders,
-			ResponseFields:     validator.responseFields,
-			ResponseExample:    responseExample,
+			entry := Entry{
+				Summary:     opt.Summary,
+				Description: description,
+				Tags:        opt.Tags,
+				OperationID: opt.OperationID,
+				Deprecated:  opt.Deprecated,
+				Replacement: opt.Replacement,
+				Since:       opt.Since,
+				Security:    security,
+
+				Method:       r.Method,
+				Path:         r.URL.Path,
+				PathTemplate: route.PathTemplate,
+				RouteName:    route.Name,
+				PathParams:   pathParams,
+				Operation:    operation,
+
+				RequestHeaders: requestHeaders,
+				RequestParams:  requestParams,
+				RequestFields:  requestFields,
+				RequestExample: requestExample,
+
+				ResponseStatusCode: rw.statusCode,
+				ResponseHeaders:    responseHeaders,
+				ResponseFields:     responseFields,
+				ResponseExample:    responseExample,
+				ResponseEvents:     rec.responseEvents,
+				RPC:                rpc,
+				GraphQL:            graphQL,
+				JSONRPC:            jsonRPC,
+				WebSocket:          webSocket,
+
+				Duration:        duration,
+				TimeToFirstByte: timeToFirstByte,
+				RequestSize:     len(rec.requestBody),
+				ResponseSize:    len(rec.responseBody),
+
+				record:         rec,
+				validated:      validator.validated(),
+				requestDecoded: requestDecoded,
+			}
+			entry.format()
+			document.Entries = append(document.Entries, entry)
 		}
-		entry.format()
-		document.Entries = append(document.Entries, entry)
 	})
 }
 
+// protoBody returns the example and fields of protocol buffer endpoint body. Protocol buffer encoded
+// body is converted into json format and it also returns true then. Every field of the message is
+// documented even if validator doesn't have the test case.
+func (d *Document) protoBody(p protoMessage, encoded bool, body []byte, example string, fields []Data) (string, []Data, bool) {
+	if !p.valid() {
+		return example, fields, false
+	}
+
+	// If body can't be decoded (e.g., error message which isn't protocol buffer), it's written as it is.
+	var (
+		m       protov2.Message
+		decoded bool
+	)
+	if encoded {
+		if s, msg, err := p.decode(body); err == nil {
+			example, m, decoded = s, msg, len(body) > 0
+		}
+	} else if len(body) > 0 {
+		m, _ = p.decodeJSON(body)
+	}
+
+	if m != nil && len(body) > 0 {
+		fields = mergeFields(fields, d.protoFields(m))
+	}
+	return example, fields, decoded
+}
+
+// protoExample unmarshals protocol buffer encoded data with the given unmarshaler and encodes it
+// into indented json format.
+func protoExample(unmarshaler proto.Unmarshaler, data []byte) (string, error) {
+	// Unmarshaler may be reused, so reset it not to merge the previous value.
+	if m, ok := unmarshaler.(interface {
+		Reset()
+	}); ok {
+		m.Reset()
+	}
+	err := unmarshaler.Unmarshal(data)
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.Encode(unmarshaler)
+	s := buf.String()
+	buf.Reset()
+	json.Indent(&buf, []byte(s), "", "  ")
+
+	return buf.String(), err
+}
+
 // format sorts entry data to prevent results updated everytime.
 func (e *Entry) format() error {
 	sort.Sort(byName(e.RequestHeaders))
 	sort.Sort(byName(e.RequestParams))
+	sort.Stable(byName(e.RequestFields))
+	sort.Stable(byName(e.ResponseFields))
 
 	return nil
 }
@@ -249,19 +629,55 @@ type responseWriter struct {
 	statusCode   int
 	responseBody []byte
 
+	// firstByteAt is time when status code or body is written first.
+	firstByteAt time.Time
+
+	// writes is the timeline of writes. It's used to know when each event of streaming response is sent.
+	writes []write
+
+	// hijacked is the connection which handler hijacked (e.g., to upgrade it to WebSocket).
+	hijacked *hijackedConn
+
 	http.ResponseWriter
 }
 
+// write is a write of response body. end is the body length after the write.
+type write struct {
+	end int
+	at  time.Time
+}
+
 func (w *responseWriter) Write(buf []byte) (int, error) {
-	w.responseBody = buf
-	return w.ResponseWriter.Write(buf)
+	w.markFirstByte()
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(buf)
+	w.responseBody = append(w.responseBody, buf[:n]...)
+	w.writes = append(w.writes, write{end: len(w.responseBody), at: time.Now()})
+	return n, err
 }
 
 func (w *responseWriter) WriteHeader(code int) {
+	w.markFirstByte()
 	w.statusCode = code
 	w.ResponseWriter.WriteHeader(code)
 }
 
+// Flush implements http.Flusher to allow handlers to stream response.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *responseWriter) markFirstByte() {
+	if w.firstByteAt.IsZero() {
+		w.firstByteAt = time.Now()
+	}
+}
+
 // convertHeaders convert HTTP header to httpdoc description format.
 func convertHeaders(headers map[string][]string) []Data {
 	d := make([]Data, 0, len(headers))
@@ -66,22 +66,25 @@ func TestRecord(t *testing.T) {
 				Path:        "/v1/hello",
 
 				RequestParams: []Data{
-					{"pretty", "true", ""},
-					{"token", "123456", ""},
+					{Name: "pretty", Value: "true"},
+					{Name: "token", Value: "{token}"},
 				},
 				RequestHeaders: []Data{
-					{"Accept-Encoding", "gzip", ""},
-					{"Content-Length", "5", ""},
-					{"User-Agent", "Go-http-client/1.1", ""},
+					{Name: "Accept-Encoding", Value: "gzip"},
+					{Name: "Content-Length", Value: "5"},
+					{Name: "User-Agent", Value: "Go-http-client/1.1"},
 				},
 				RequestFields:  nil,
 				RequestExample: "hello",
 
 				ResponseStatusCode: http.StatusOK,
 				ResponseHeaders: []Data{
-					{"Content-Type", "text/plain", ""},
+					{Name: "Content-Type", Value: "text/plain"},
 				},
 				ResponseExample: "hello",
+
+				RequestSize:  5,
+				ResponseSize: 5,
 			},
 		},
 
@@ -89,13 +92,25 @@ func TestRecord(t *testing.T) {
 			"/v1/hello",
 			testHandler,
 			&RecordOption{
+				Summary:        "Say hello",
+				Tags:           []string{"greeting"},
+				OperationID:    "hello",
+				Deprecated:     true,
+				Replacement:    "GET /v2/hello",
+				Since:          "v1.0.0",
 				ExcludeHeaders: testExcludeHeaders,
 			},
 			"GET",
 			"",
 			strings.NewReader("hello"),
 			Entry{
+				Summary:     "Say hello",
 				Description: "",
+				Tags:        []string{"greeting"},
+				OperationID: "hello",
+				Deprecated:  true,
+				Replacement: "GET /v2/hello",
+				Since:       "v1.0.0",
 				Method:      "GET",
 				Path:        "/v1/hello",
 
@@ -106,9 +121,12 @@ func TestRecord(t *testing.T) {
 
 				ResponseStatusCode: http.StatusOK,
 				ResponseHeaders: []Data{
-					{"Content-Type", "text/plain", ""},
+					{Name: "Content-Type", Value: "text/plain"},
 				},
 				ResponseExample: "hello",
+
+				RequestSize:  5,
+				ResponseSize: 5,
 			},
 		},
 
@@ -132,7 +150,7 @@ func TestRecord(t *testing.T) {
 				Path:        "/v1/hello",
 
 				RequestParams: []Data{
-					{"token", "123456", "Test token"},
+					{Name: "token", Value: "{token}", Description: "Test token"},
 				},
 				RequestHeaders: []Data{},
 				RequestFields:  nil,
@@ -140,9 +158,12 @@ func TestRecord(t *testing.T) {
 
 				ResponseStatusCode: http.StatusOK,
 				ResponseHeaders: []Data{
-					{"Content-Type", "text/plain", ""},
+					{Name: "Content-Type", Value: "text/plain"},
 				},
 				ResponseExample: "hello",
+
+				RequestSize:  5,
+				ResponseSize: 5,
 			},
 		},
 	}
@@ -168,6 +189,11 @@ func TestRecord(t *testing.T) {
 		}
 
 		got := document.Entries[0]
+		got.record, got.validated = nil, nil
+		if got.Duration <= 0 || got.TimeToFirstByte <= 0 || got.TimeToFirstByte > got.Duration {
+			t.Fatalf("expect duration and time to first byte to be recorded: %v, %v", got.Duration, got.TimeToFirstByte)
+		}
+		got.Duration, got.TimeToFirstByte = 0, 0
 		if !reflect.DeepEqual(got, tc.want) {
 			t.Fatalf("\ngot  %#v\nwant %#v", got, tc.want)
 		}
@@ -208,7 +234,10 @@ func TestRecord_Proto(t *testing.T) {
 
 				RequestParams:  []Data{},
 				RequestHeaders: []Data{},
-				RequestFields:  nil,
+				RequestFields: []Data{
+					{Name: "Id", Value: int32(7089), Schema: Schema{Type: "integer", Format: "int32"}},
+					{Name: "Name", Value: "tcnksm", Schema: Schema{Type: "string"}},
+				},
 				RequestExample: `{
   "id": 7089,
   "name": "tcnksm"
@@ -217,7 +246,14 @@ func TestRecord_Proto(t *testing.T) {
 
 				ResponseStatusCode: http.StatusOK,
 				ResponseHeaders: []Data{
-					{"Content-Type", "application/protobuf", ""},
+					{Name: "Content-Type", Value: "application/protobuf"},
+				},
+				ResponseFields: []Data{
+					{Name: "Active", Value: true, Schema: Schema{Type: "boolean"}},
+					{Name: "Id", Value: int32(7089), Schema: Schema{Type: "integer", Format: "int32"}},
+					{Name: "Name", Value: "tcnksm", Schema: Schema{Type: "string"}},
+					{Name: "Setting", Value: "", Schema: Schema{Type: "object", Format: "httpdoc.UserProtoResponse.Setting"}},
+					{Name: "Setting.Email", Value: "", Schema: Schema{Type: "string"}},
 				},
 				ResponseExample: `{
   "id": 7089,
@@ -225,6 +261,11 @@ func TestRecord_Proto(t *testing.T) {
   "active": true
 }
 `,
+
+				RequestSize:  11,
+				ResponseSize: 13,
+
+				requestDecoded: true,
 			},
 		},
 	}
@@ -254,6 +295,11 @@ func TestRecord_Proto(t *testing.T) {
 		}
 
 		got := document.Entries[0]
+		got.record, got.validated = nil, nil
+		if got.Duration <= 0 || got.TimeToFirstByte <= 0 || got.TimeToFirstByte > got.Duration {
+			t.Fatalf("expect duration and time to first byte to be recorded: %v, %v", got.Duration, got.TimeToFirstByte)
+		}
+		got.Duration, got.TimeToFirstByte = 0, 0
 		if !reflect.DeepEqual(got, tc.want) {
 			t.Fatalf("\ngot  %#v\nwant %#v", got, tc.want)
 		}
@@ -338,6 +384,22 @@ func TestExcludeData(t *testing.T) {
 }
 
 func TestResponseWriter_Write(t *testing.T) {
+	rw := &responseWriter{ResponseWriter: httptest.NewRecorder()}
+	rw.Write([]byte("hello "))
+	rw.Write([]byte("world"))
+
+	if got, want := rw.statusCode, http.StatusOK; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := string(rw.responseBody), "hello world"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := len(rw.writes), 2; got != want {
+		t.Fatalf("got %d writes, want %d", got, want)
+	}
+	if got, want := rw.writes[1].end, 11; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
 }
 
 func TestResponseWriter_WriteHeader(t *testing.T) {
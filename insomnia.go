@@ -0,0 +1,153 @@
+package httpdoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+const (
+	insomniaWorkspaceID   = "wrk_httpdoc"
+	insomniaEnvironmentID = "env_httpdoc"
+)
+
+type insomniaExport struct {
+	Type         string             `json:"_type"`
+	ExportFormat int                `json:"__export_format"`
+	ExportSource string             `json:"__export_source"`
+	Resources    []insomniaResource `json:"resources"`
+}
+
+// insomniaResource is a resource in Insomnia export. Which fields are used depends on the type.
+type insomniaResource struct {
+	ID       string `json:"_id"`
+	Type     string `json:"_type"`
+	ParentID string `json:"parentId,omitempty"`
+	Name     string `json:"name"`
+
+	// Used by request.
+	Method      string         `json:"method,omitempty"`
+	URL         string         `json:"url,omitempty"`
+	Parameters  []insomniaPair `json:"parameters,omitempty"`
+	Headers     []insomniaPair `json:"headers,omitempty"`
+	Body        *insomniaBody  `json:"body,omitempty"`
+	Description string         `json:"description,omitempty"`
+
+	// Used by environment.
+	Data map[string]string `json:"data,omitempty"`
+}
+
+type insomniaPair struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type insomniaBody struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// WriteInsomnia writes recorded entries as Insomnia export (format v4). Entries are grouped into
+// request groups by path prefix and entries which have the same method and path are merged into
+// one request.
+//
+// The base URL is set as `base_url` environment variable. Secret values (see Document.Secrets) are
+// replaced with environment variables whose values are empty. Since Insomnia does not have a way to
+// save example responses, they are written in the request description.
+func (d *Document) WriteInsomnia(w io.Writer) error {
+	environment := insomniaResource{
+		ID:       insomniaEnvironmentID,
+		Type:     "environment",
+		ParentID: insomniaWorkspaceID,
+		Name:     "Base Environment",
+		Data: map[string]string{
+			"base_url": d.baseURL(),
+		},
+	}
+
+	export := insomniaExport{
+		Type:         "export",
+		ExportFormat: 4,
+		ExportSource: "httpdoc",
+		Resources: []insomniaResource{
+			{
+				ID:   insomniaWorkspaceID,
+				Type: "workspace",
+				Name: d.Name,
+			},
+		},
+	}
+
+	var requests []insomniaResource
+	folders := make(map[string]string)
+	for i, group := range groupEntries(d.Entries) {
+		e := group[0]
+
+		prefix := pathPrefix(e.Path)
+		folderID, ok := folders[prefix]
+		if !ok {
+			folderID = fmt.Sprintf("fld_%d", len(folders)+1)
+			folders[prefix] = folderID
+			export.Resources = append(export.Resources, insomniaResource{
+				ID:       folderID,
+				Type:     "request_group",
+				ParentID: insomniaWorkspaceID,
+				Name:     prefix,
+			})
+		}
+
+		value := func(name string, v interface{}) string {
+			if d.isSecret(name) {
+				environment.Data[variableName(name)] = ""
+				return "{{ _." + variableName(name) + " }}"
+			}
+			return fmt.Sprint(v)
+		}
+
+		request := insomniaResource{
+			ID:          fmt.Sprintf("req_%d", i+1),
+			Type:        "request",
+			ParentID:    folderID,
+			Name:        e.Method + " " + e.Path,
+			Method:      e.Method,
+			URL:         "{{ _.base_url }}" + e.Path,
+			Description: insomniaDescription(group),
+		}
+		for _, p := range e.requestParams() {
+			request.Parameters = append(request.Parameters, insomniaPair{Name: p.Name, Value: value(p.Name, p.Value)})
+		}
+		for _, h := range e.requestHeaders() {
+			request.Headers = append(request.Headers, insomniaPair{Name: h.Name, Value: value(h.Name, h.Value)})
+		}
+		if e.RequestExample != "" {
+			request.Body = &insomniaBody{
+				MimeType: contentType(e.requestHeaders()),
+				Text:     e.RequestExample,
+			}
+		}
+		requests = append(requests, request)
+	}
+
+	export.Resources = append(export.Resources, environment)
+	export.Resources = append(export.Resources, requests...)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(&export)
+}
+
+// insomniaDescription returns markdown description with example responses of the entries.
+func insomniaDescription(entries []Entry) string {
+	var buf bytes.Buffer
+	if entries[0].Description != "" {
+		fmt.Fprintf(&buf, "%s\n\n", entries[0].Description)
+	}
+	for _, e := range entries {
+		fmt.Fprintf(&buf, "### Response [%d]\n\n", e.ResponseStatusCode)
+		if e.ResponseExample != "" {
+			fmt.Fprintf(&buf, "```\n%s\n```\n\n", e.ResponseExample)
+		}
+	}
+	return buf.String()
+}
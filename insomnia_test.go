@@ -0,0 +1,92 @@
+package httpdoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDocument_WriteInsomnia(t *testing.T) {
+	doc := &Document{
+		Name:    "Example API",
+		Entries: testExportEntries,
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WriteInsomnia(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got insomniaExport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.ExportFormat != 4 {
+		t.Fatalf("got %d, want %d", got.ExportFormat, 4)
+	}
+
+	resources := make(map[string][]insomniaResource)
+	for _, r := range got.Resources {
+		resources[r.Type] = append(resources[r.Type], r)
+	}
+
+	if got, want := len(resources["workspace"]), 1; got != want {
+		t.Fatalf("got %d workspaces, want %d", got, want)
+	}
+	if got, want := len(resources["request_group"]), 2; got != want {
+		t.Fatalf("got %d request groups, want %d", got, want)
+	}
+	if got, want := len(resources["request"]), 3; got != want {
+		t.Fatalf("got %d requests, want %d", got, want)
+	}
+
+	env := resources["environment"][0]
+	if got, want := env.Data["base_url"], defaultBaseURL; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if _, ok := env.Data["token"]; !ok {
+		t.Fatalf("expect token variable to be defined")
+	}
+
+	request := resources["request"][0]
+	if got, want := request.Parameters[1].Value, "{{ _.token }}"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := request.Body.MimeType, "application/json"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := request.Description, "### Response [400]"; !strings.Contains(got, want) {
+		t.Fatalf("expect %q to contain %q", got, want)
+	}
+}
+
+func TestDocument_WriteInsomnia_Proto(t *testing.T) {
+	doc := &Document{Entries: recordProtoEntries(t)}
+
+	var buf bytes.Buffer
+	if err := doc.WriteInsomnia(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got insomniaExport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	// The json example decoded from protocol buffer encoded body is sent as json.
+	for _, r := range got.Resources {
+		if r.Type != "request" {
+			continue
+		}
+		if r.Body == nil || r.Body.MimeType != "application/json" {
+			t.Fatalf("expect body to be sent as json: %#v", r.Body)
+		}
+		for _, h := range r.Headers {
+			if h.Name == "Content-Type" && h.Value != "application/json" {
+				t.Fatalf("got %q, want %q", h.Value, "application/json")
+			}
+		}
+	}
+}
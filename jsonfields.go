@@ -0,0 +1,93 @@
+package httpdoc
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// jsonFields returns every field of the decoded json value including fields of nested objects and
+// arrays. Field names are prefixed with prefix (e.g., `data.user.name`) and elements of arrays are
+// `[*]`, so that they are same as TestCase.Target. Values of objects and arrays are not written,
+// they are documented by their fields. If elements of arrays have the same field, the first one is
+// used. Nothing is returned for null.
+func jsonFields(prefix string, v interface{}) []Data {
+	var fields []Data
+	seen := make(map[string]bool)
+
+	var walk func(name string, v interface{})
+	walk = func(name string, v interface{}) {
+		_, container := v.(map[string]interface{})
+		if _, ok := v.([]interface{}); ok {
+			container = true
+		}
+
+		// Elements of objects or arrays are documented by their fields, e.g., `items[*].id`.
+		if !seen[name] && !(container && strings.HasSuffix(name, "[*]")) {
+			seen[name] = true
+			data := Data{Name: name, Value: v, Schema: Schema{Type: jsonType(v)}}
+			if container {
+				data.Value = ""
+			}
+			fields = append(fields, data)
+		}
+
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for _, k := range sortedKeys(v) {
+				walk(jsonFieldName(name, k), v[k])
+			}
+		case []interface{}:
+			for _, e := range v {
+				walk(name+"[*]", e)
+			}
+		}
+	}
+
+	switch m := v.(type) {
+	case nil:
+	case map[string]interface{}:
+		for _, k := range sortedKeys(m) {
+			walk(jsonFieldName(prefix, k), m[k])
+		}
+	default:
+		if prefix != "" {
+			walk(prefix, v)
+		}
+	}
+	return fields
+}
+
+// jsonFieldName returns the field path of the key in the parent. Keys which can't be written as
+// field names (e.g., `a.b`) are written as map keys (`["a.b"]`).
+func jsonFieldName(parent, key string) string {
+	if key == "" || strings.ContainsAny(key, ".[]\"`") {
+		return parent + "[" + strconv.Quote(key) + "]"
+	}
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// jsonType returns JSON type name of the decoded value, e.g., `string` or `array of number`.
+func jsonType(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, json.Number:
+		return "number"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		if len(v) > 0 {
+			return "array of " + jsonType(v[0])
+		}
+		return "array"
+	}
+	return ""
+}
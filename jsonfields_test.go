@@ -0,0 +1,39 @@
+package httpdoc
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestJSONFields(t *testing.T) {
+	var v interface{}
+	if err := json.Unmarshal([]byte(`{
+		"name": "tcnksm",
+		"tags": ["a", "b"],
+		"items": [{"id": 1}, {"id": 2, "note": null}],
+		"a.b": true
+	}`), &v); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	want := []Data{
+		{Name: `body["a.b"]`, Value: true, Schema: Schema{Type: "boolean"}},
+		{Name: "body.items", Value: "", Schema: Schema{Type: "array of object"}},
+		{Name: "body.items[*].id", Value: float64(1), Schema: Schema{Type: "number"}},
+		{Name: "body.items[*].note", Value: nil, Schema: Schema{Type: "null"}},
+		{Name: "body.name", Value: "tcnksm", Schema: Schema{Type: "string"}},
+		{Name: "body.tags", Value: "", Schema: Schema{Type: "array of string"}},
+		{Name: "body.tags[*]", Value: "a", Schema: Schema{Type: "string"}},
+	}
+	if got := jsonFields("body", v); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+
+	if got := jsonFields("", map[string]interface{}{"id": "1"}); !reflect.DeepEqual(got, []Data{{Name: "id", Value: "1", Schema: Schema{Type: "string"}}}) {
+		t.Fatalf("got %#v", got)
+	}
+	if got := jsonFields("data", nil); got != nil {
+		t.Fatalf("expect nothing for null: %#v", got)
+	}
+}
@@ -0,0 +1,181 @@
+package httpdoc
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// JSONRPCCall is JSON-RPC 2.0 call of the entry. Normally, you don't need to modify this.
+// All fields are exported just for templating.
+type JSONRPCCall struct {
+	// Method is the method name.
+	Method string
+
+	// ID is the request id in JSON, e.g., `1` or `"abc"`. It's empty for notifications.
+	ID string
+
+	// Batch reports whether the call is sent in a batch request.
+	Batch bool
+
+	// Error is the error of the response. It's nil if the call succeeded or it's a notification.
+	Error *JSONRPCError
+}
+
+// JSONRPCError is the error object of JSON-RPC 2.0 response.
+type JSONRPCError struct {
+	// Code is the error code, e.g., `-32601`.
+	Code int
+
+	// Message is the error message.
+	Message string
+
+	// Data is additional information of the error in JSON. It's empty if it's not given.
+	Data string
+}
+
+// jsonRPCErrorMeanings is meanings of error codes defined by JSON-RPC 2.0 specification.
+var jsonRPCErrorMeanings = map[int]string{
+	-32700: "Parse error",
+	-32600: "Invalid Request",
+	-32601: "Method not found",
+	-32602: "Invalid params",
+	-32603: "Internal error",
+}
+
+// Meaning returns the meaning of the error code defined by JSON-RPC 2.0 specification, e.g.,
+// `Method not found`. Codes from -32000 to -32099 are `Server error`. It's empty for codes which
+// application defines.
+func (e *JSONRPCError) Meaning() string {
+	if meaning, ok := jsonRPCErrorMeanings[e.Code]; ok {
+		return meaning
+	}
+	if -32099 <= e.Code && e.Code <= -32000 {
+		return "Server error"
+	}
+	return ""
+}
+
+// jsonRPCExchange is a call in JSON-RPC request and its response. Bodies of the record are the
+// request and response objects of the call. call is nil if the request is not JSON-RPC.
+type jsonRPCExchange struct {
+	record *record
+	call   *JSONRPCCall
+	params interface{}
+	result interface{}
+}
+
+// jsonRPCExchanges splits JSON-RPC request and response of the record into calls. Responses are
+// matched with requests by id. Requests which are not valid JSON-RPC (e.g., without method) are
+// ignored. nil is returned if the request is not JSON-RPC.
+func jsonRPCExchanges(rec *record) []jsonRPCExchange {
+	requests, batch := jsonRPCMessages(rec.requestBody)
+	responses, _ := jsonRPCMessages(rec.responseBody)
+
+	var exchanges []jsonRPCExchange
+	for _, raw := range requests {
+		var req struct {
+			Method string          `json:"method"`
+			ID     json.RawMessage `json:"id"`
+			Params interface{}     `json:"params"`
+		}
+		if err := json.Unmarshal(raw, &req); err != nil || req.Method == "" {
+			continue
+		}
+
+		x := jsonRPCExchange{
+			call:   &JSONRPCCall{Method: req.Method, ID: compactJSON(req.ID), Batch: batch},
+			params: req.Params,
+		}
+
+		var response json.RawMessage
+		if x.call.ID != "" {
+			response = jsonRPCResponse(responses, x.call.ID, batch)
+		}
+		if response != nil {
+			var res struct {
+				Result interface{} `json:"result"`
+				Error  *struct {
+					Code    int             `json:"code"`
+					Message string          `json:"message"`
+					Data    json.RawMessage `json:"data"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(response, &res); err == nil {
+				x.result = res.Result
+				if res.Error != nil {
+					x.call.Error = &JSONRPCError{
+						Code:    res.Error.Code,
+						Message: res.Error.Message,
+						Data:    compactJSON(res.Error.Data),
+					}
+				}
+			}
+		}
+
+		r := *rec
+		r.requestBody = raw
+		r.responseBody = response
+		r.responseEvents = nil
+		x.record = &r
+		exchanges = append(exchanges, x)
+	}
+	return exchanges
+}
+
+// jsonRPCMessages returns messages in the body. Batch reports whether the body is an array of
+// messages.
+func jsonRPCMessages(body []byte) ([]json.RawMessage, bool) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var messages []json.RawMessage
+		if err := json.Unmarshal(body, &messages); err != nil {
+			return nil, true
+		}
+		return messages, true
+	}
+	if len(body) == 0 {
+		return nil, false
+	}
+	return []json.RawMessage{body}, false
+}
+
+// jsonRPCResponse returns the response of the id. Without batch, the only response is returned
+// even if the id is different (e.g., `null` for parse errors).
+func jsonRPCResponse(responses []json.RawMessage, id string, batch bool) json.RawMessage {
+	if !batch && len(responses) == 1 {
+		return responses[0]
+	}
+	for _, raw := range responses {
+		var res struct {
+			ID json.RawMessage `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &res); err == nil && compactJSON(res.ID) == id {
+			return raw
+		}
+	}
+	return nil
+}
+
+// compactJSON returns compact form of the JSON. It's empty if the JSON is empty.
+func compactJSON(raw json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
+// JSONRPC returns the JSON-RPC call which the validator validates. WithValidate is called for each
+// call of batch request, so use the method of the call to choose test cases. It's nil if the
+// endpoint is not recorded as JSON-RPC endpoint (see RecordOption.JSONRPC).
+//
+//   WithValidate: func(validator *httpdoc.Validator) {
+//       switch validator.JSONRPC().Method {
+//       case "user.get":
+//           validator.RequestBody(t, []httpdoc.TestCase{httpdoc.NewTestCase("params.id", 1, "User ID")}, nil)
+//       }
+//   },
+//
+func (v *Validator) JSONRPC() *JSONRPCCall {
+	return v.jsonRPC
+}
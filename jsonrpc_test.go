@@ -0,0 +1,149 @@
+package httpdoc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONRPCError_Meaning(t *testing.T) {
+	cases := map[int]string{
+		-32700: "Parse error",
+		-32601: "Method not found",
+		-32050: "Server error",
+		-32100: "",
+		1001:   "",
+	}
+	for code, want := range cases {
+		if got := (&JSONRPCError{Code: code}).Meaning(); got != want {
+			t.Fatalf("%d: got %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestRecord_JSONRPC(t *testing.T) {
+	doc := &Document{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"jsonrpc": "2.0", "id": "b", "error": {"code": -32601, "message": "Method not found", "data": {"method": "user.delete"}}},
+			{"jsonrpc": "2.0", "id": 1, "result": {"name": "tcnksm", "tags": ["admin"]}}
+		]`))
+	})
+
+	var validated []string
+	ts := httptest.NewServer(Record(handler, doc, &RecordOption{
+		JSONRPC: true,
+		WithValidate: func(validator *Validator) {
+			validated = append(validated, validator.JSONRPC().Method)
+			if validator.JSONRPC().Method != "user.get" {
+				return
+			}
+			validator.RequestBody(t, []TestCase{
+				NewTestCase("params.id", float64(12345), "User ID"),
+			}, nil)
+			validator.ResponseBody(t, []TestCase{
+				NewTestCase("result.name", "tcnksm", "User name"),
+			}, nil)
+		},
+	}))
+	defer ts.Close()
+
+	res, err := http.Post(ts.URL+"/rpc", "application/json", strings.NewReader(`[
+		{"jsonrpc": "2.0", "id": 1, "method": "user.get", "params": {"id": 12345}},
+		{"jsonrpc": "2.0", "method": "user.touch", "params": [12345]},
+		{"jsonrpc": "2.0", "id": "b", "method": "user.delete"}
+	]`))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	if want := []string{"user.get", "user.touch", "user.delete"}; !reflect.DeepEqual(validated, want) {
+		t.Fatalf("expect validator to be called for each call: got %q, want %q", validated, want)
+	}
+
+	var titles []string
+	for _, e := range doc.Entries {
+		titles = append(titles, e.Title())
+	}
+	if want := []string{
+		"[200] POST /rpc user.get",
+		"[200] POST /rpc user.touch",
+		"[200] POST /rpc user.delete",
+	}; !reflect.DeepEqual(titles, want) {
+		t.Fatalf("got %q, want %q", titles, want)
+	}
+
+	get := doc.Entries[0]
+	if want := (&JSONRPCCall{Method: "user.get", ID: "1", Batch: true}); !reflect.DeepEqual(get.JSONRPC, want) {
+		t.Fatalf("got %#v, want %#v", get.JSONRPC, want)
+	}
+	if want := []Data{{Name: "params.id", Value: float64(12345), Description: "User ID", Schema: Schema{Type: "number"}}}; !reflect.DeepEqual(get.RequestFields, want) {
+		t.Fatalf("got %#v, want %#v", get.RequestFields, want)
+	}
+	var fields []string
+	for _, f := range get.ResponseFields {
+		fields = append(fields, f.Name+"|"+f.Type+"|"+f.Description)
+	}
+	if want := []string{"result.name|string|User name", "result.tags|array of string|", "result.tags[*]|string|"}; !reflect.DeepEqual(fields, want) {
+		t.Fatalf("got %q, want %q", fields, want)
+	}
+	if want := `"result": {"name": "tcnksm"`; !strings.Contains(get.ResponseExample, want) {
+		t.Fatalf("expect %q to contain %q", get.ResponseExample, want)
+	}
+
+	if touch := doc.Entries[1]; touch.JSONRPC.ID != "" || touch.ResponseExample != "" {
+		t.Fatalf("expect notification not to have response: %#v, %q", touch.JSONRPC, touch.ResponseExample)
+	}
+
+	want := &JSONRPCError{Code: -32601, Message: "Method not found", Data: `{"method":"user.delete"}`}
+	if got := doc.Entries[2].JSONRPC.Error; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.generate(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, want := range []string{
+		"JSON-RPC request `user.get` (id: `1`) in batch",
+		"JSON-RPC notification `user.touch` in batch",
+		"| -32601 | Method not found | Method not found | `{\"method\":\"user.delete\"}` |",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expect %q to contain %q", buf.String(), want)
+		}
+	}
+}
+
+func TestRecord_JSONRPC_Single(t *testing.T) {
+	doc := &Document{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.Write([]byte(`{"jsonrpc": "2.0", "id": null, "error": {"code": -32700, "message": "Parse error"}}`))
+	})
+
+	ts := httptest.NewServer(Record(handler, doc, &RecordOption{JSONRPC: true}))
+	defer ts.Close()
+
+	for _, body := range []string{`{"jsonrpc": "2.0", "id": 1, "method": "ping"}`, `not json`} {
+		res, err := http.Post(ts.URL+"/rpc", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		res.Body.Close()
+	}
+
+	if e := doc.Entries[0]; e.Operation != "ping" || e.JSONRPC.Batch || e.JSONRPC.Error.Meaning() != "Parse error" {
+		t.Fatalf("got %q, %#v", e.Operation, e.JSONRPC)
+	}
+	if e := doc.Entries[1]; e.JSONRPC != nil || e.RequestExample != "not json" {
+		t.Fatalf("expect request which is not JSON-RPC to be recorded as it is: %#v", e.JSONRPC)
+	}
+}
@@ -0,0 +1,194 @@
+package httpdoc
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Matcher matches the actual value instead of comparing with TestCase.Expected by equality. It's
+// useful for values which change on every request (e.g., IDs or timestamps). Matcher can be used
+// as TestCase.Expected and String is written in documentation as the value.
+//
+//   validator.ResponseBody(t, []httpdoc.TestCase{
+//       NewTestCase("ID", httpdoc.IsUUID(), "User ID"),
+//       NewTestCase("Items", httpdoc.Len(3), "User items"),
+//   }, &User{})
+//
+type Matcher interface {
+	// Match reports whether the actual value is matched.
+	Match(actual interface{}) bool
+
+	// String returns the description of matched values.
+	String() string
+}
+
+type matcher struct {
+	desc  string
+	match func(actual interface{}) bool
+}
+
+func (m *matcher) Match(actual interface{}) bool { return m.match(actual) }
+func (m *matcher) String() string                { return m.desc }
+
+// AnyString matches any string.
+func AnyString() Matcher {
+	return &matcher{
+		desc: "any string",
+		match: func(actual interface{}) bool {
+			_, ok := stringValue(actual)
+			return ok
+		},
+	}
+}
+
+// Regexp matches strings which match the regular expression. It panics if the expression cannot be parsed.
+func Regexp(expr string) Matcher {
+	re := regexp.MustCompile(expr)
+	return &matcher{
+		desc: fmt.Sprintf("matches `%s`", expr),
+		match: func(actual interface{}) bool {
+			s, ok := stringValue(actual)
+			return ok && re.MatchString(s)
+		},
+	}
+}
+
+// OneOf matches values which are equal to one of the given values.
+func OneOf(values ...interface{}) Matcher {
+	descs := make([]string, len(values))
+	for i, v := range values {
+		descs[i] = fmt.Sprint(v)
+	}
+	return &matcher{
+		desc: "one of " + strings.Join(descs, ", "),
+		match: func(actual interface{}) bool {
+			for _, v := range values {
+				if reflect.DeepEqual(v, actual) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// IsUUID matches UUID strings, e.g., `6ba7b810-9dad-11d1-80b4-00c04fd430c8`.
+// The version is written in documentation when it's given, e.g., `UUID v4`.
+func IsUUID(version ...int) Matcher {
+	desc := "UUID"
+	if len(version) > 0 {
+		desc = fmt.Sprintf("UUID v%d", version[0])
+	}
+	return &matcher{
+		desc: desc,
+		match: func(actual interface{}) bool {
+			s, ok := stringValue(actual)
+			if !ok || !uuidRegexp.MatchString(s) {
+				return false
+			}
+			return len(version) == 0 || fmt.Sprint(version[0]) == s[14:15]
+		},
+	}
+}
+
+// IsRFC3339 matches RFC 3339 date-time strings (e.g., `2017-11-24T10:00:00Z`) and time.Time values.
+func IsRFC3339() Matcher {
+	return &matcher{
+		desc: "RFC 3339 date-time",
+		match: func(actual interface{}) bool {
+			switch v := actual.(type) {
+			case time.Time:
+				return true
+			case *time.Time:
+				return v != nil
+			}
+			s, ok := stringValue(actual)
+			if !ok {
+				return false
+			}
+			_, err := time.Parse(time.RFC3339Nano, s)
+			return err == nil
+		},
+	}
+}
+
+// GreaterThan matches numbers which are greater than n. Any integer and floating point types
+// can be compared.
+func GreaterThan(n float64) Matcher {
+	return &matcher{
+		desc: fmt.Sprintf("greater than %v", n),
+		match: func(actual interface{}) bool {
+			f, ok := floatValue(actual)
+			return ok && f > n
+		},
+	}
+}
+
+// Len matches strings, slices, arrays and maps whose length is n.
+func Len(n int) Matcher {
+	return &matcher{
+		desc: fmt.Sprintf("length %d", n),
+		match: func(actual interface{}) bool {
+			v := reflect.ValueOf(actual)
+			switch v.Kind() {
+			case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+				return v.Len() == n
+			}
+			return false
+		},
+	}
+}
+
+// Not matches values which are not matched by m.
+func Not(m Matcher) Matcher {
+	return &matcher{
+		desc: "not " + m.String(),
+		match: func(actual interface{}) bool {
+			return !m.Match(actual)
+		},
+	}
+}
+
+// Nil matches nil and nil pointers, slices, maps and interfaces.
+func Nil() Matcher {
+	return &matcher{
+		desc: "nil",
+		match: func(actual interface{}) bool {
+			if actual == nil {
+				return true
+			}
+			v := reflect.ValueOf(actual)
+			switch v.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface, reflect.Chan, reflect.Func:
+				return v.IsNil()
+			}
+			return false
+		},
+	}
+}
+
+// stringValue returns the string if the value is a string or a named string type.
+func stringValue(v interface{}) (string, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.String {
+		return "", false
+	}
+	return rv.String(), true
+}
+
+// floatValue converts integer and floating point values to float64.
+func floatValue(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
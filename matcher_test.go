@@ -0,0 +1,95 @@
+package httpdoc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Status string
+
+func TestMatchers(t *testing.T) {
+	now := time.Now()
+	var nilSetting *Setting
+
+	cases := []struct {
+		matcher Matcher
+		desc    string
+		match   []interface{}
+		unmatch []interface{}
+	}{
+		{AnyString(), "any string", []interface{}{"", "a", Status("active")}, []interface{}{nil, 1}},
+		{Regexp(`^u-\d+$`), "matches `^u-\\d+$`", []interface{}{"u-1"}, []interface{}{"u-a", 1}},
+		{OneOf("a", 1), "one of a, 1", []interface{}{"a", 1}, []interface{}{"1", int64(1)}},
+		{IsUUID(), "UUID", []interface{}{"6ba7b810-9dad-11d1-80b4-00c04fd430c8", "f47ac10b-58cc-4372-a567-0e02b2c3d479"}, []interface{}{"6ba7b810", 1}},
+		{IsUUID(4), "UUID v4", []interface{}{"f47ac10b-58cc-4372-a567-0e02b2c3d479"}, []interface{}{"6ba7b810-9dad-11d1-80b4-00c04fd430c8"}},
+		{IsRFC3339(), "RFC 3339 date-time", []interface{}{"2017-11-24T10:00:00Z", "2017-11-24T10:00:00.123+09:00", now, &now}, []interface{}{"2017-11-24", 1}},
+		{GreaterThan(1), "greater than 1", []interface{}{2, uint8(2), 1.5}, []interface{}{1, -1, "2"}},
+		{Len(2), "length 2", []interface{}{"ab", []int{1, 2}, map[string]int{"a": 1, "b": 2}}, []interface{}{"a", []int{}, nil}},
+		{Not(Nil()), "not nil", []interface{}{1, &now}, []interface{}{nil, nilSetting}},
+		{Nil(), "nil", []interface{}{nil, nilSetting, []string(nil)}, []interface{}{"", 0}},
+	}
+
+	for _, tc := range cases {
+		if got := tc.matcher.String(); got != tc.desc {
+			t.Fatalf("got %q, want %q", got, tc.desc)
+		}
+		for _, v := range tc.match {
+			if !tc.matcher.Match(v) {
+				t.Fatalf("%s: expect %#v to match", tc.desc, v)
+			}
+		}
+		for _, v := range tc.unmatch {
+			if tc.matcher.Match(v) {
+				t.Fatalf("%s: expect %#v not to match", tc.desc, v)
+			}
+		}
+	}
+}
+
+func TestDefaultAssertFunc_Matcher(t *testing.T) {
+	var buf bytes.Buffer
+	tFatalf = fprintFatalFunc(&buf)
+
+	defaultAssertFunc(t, IsUUID(), "f47ac10b-58cc-4372-a567-0e02b2c3d479", "ID")
+	if buf.Len() != 0 {
+		t.Fatalf("expect not to fail: %s", buf.String())
+	}
+
+	defaultAssertFunc(t, IsUUID(), "12345", "ID")
+	if got, want := buf.String(), `ID: got "12345"(string), want UUID`; !strings.Contains(got, want) {
+		t.Fatalf("expect %q to contain %q", got, want)
+	}
+}
+
+func TestDocument_Generate_Matcher(t *testing.T) {
+	doc := &Document{
+		Entries: []Entry{
+			{
+				Method:             "GET",
+				Path:               "/v1/user",
+				ResponseStatusCode: 200,
+				ResponseFields:     []Data{{Name: "ID", Value: IsUUID(4), Description: "User ID"}},
+			},
+			{
+				Method:             "GET",
+				Path:               "/v1/user",
+				ResponseStatusCode: 200,
+				ResponseFields:     []Data{{Name: "ID", Value: IsUUID(4), Description: "User ID"}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := doc.generate(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if want := "| ID |  |  | UUID v4 | User ID |"; !strings.Contains(buf.String(), want) {
+		t.Fatalf("expect %q to contain %q", buf.String(), want)
+	}
+	if got := strings.Count(buf.String(), "## [200] GET /v1/user"); got != 1 {
+		t.Fatalf("expect entries with the same matchers to be written once, got %d", got)
+	}
+}
@@ -0,0 +1,86 @@
+// Package muxdoc provides gorilla/mux middleware which records requests by httpdoc with the matched
+// route template, route name and path variables.
+package muxdoc // import "go.mercari.io/go-httpdoc/muxdoc"
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	httpdoc "go.mercari.io/go-httpdoc"
+)
+
+// Middleware returns mux middleware which records requests in the document like httpdoc.Record.
+// The path template (e.g., `/users/{id:[0-9]+}`) is used as Entry.PathTemplate, the route name is
+// used as Entry.RouteName and path variables are used as Entry.PathParams.
+//
+//   r := mux.NewRouter()
+//   r.Use(muxdoc.Middleware(document, nil))
+//   r.HandleFunc("/users/{id}", getUser).Methods("GET").Name("getUser")
+//
+func Middleware(document *httpdoc.Document, opt *httpdoc.RecordOption) mux.MiddlewareFunc {
+	o := httpdoc.RecordOption{}
+	if opt != nil {
+		o = *opt
+	}
+	o.RouteResolver = resolveRoute
+
+	return func(next http.Handler) http.Handler {
+		return httpdoc.Record(next, document, &o)
+	}
+}
+
+// resolveRoute returns the route which mux matched. Variables are ordered as the path template.
+func resolveRoute(r *http.Request) *httpdoc.Route {
+	current := mux.CurrentRoute(r)
+	if current == nil {
+		return nil
+	}
+	template, err := current.GetPathTemplate()
+	if err != nil {
+		return nil
+	}
+
+	route := &httpdoc.Route{
+		PathTemplate: template,
+		Name:         current.GetName(),
+	}
+	vars := mux.Vars(r)
+	for _, name := range varNames(template) {
+		if v, ok := vars[name]; ok {
+			route.Params = append(route.Params, httpdoc.Data{Name: name, Value: v})
+		}
+	}
+	return route
+}
+
+// varNames returns names of variables in the template, e.g., `id` of `{id:[0-9]{3}}`. Braces in
+// patterns are balanced.
+func varNames(template string) []string {
+	var (
+		names []string
+		depth int
+		start int
+	)
+	for i := 0; i < len(template); i++ {
+		switch template[i] {
+		case '{':
+			if depth == 0 {
+				start = i + 1
+			}
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				name := template[start:i]
+				for j := 0; j < len(name); j++ {
+					if name[j] == ':' {
+						name = name[:j]
+						break
+					}
+				}
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
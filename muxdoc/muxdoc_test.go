@@ -0,0 +1,57 @@
+package muxdoc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/mux"
+	httpdoc "go.mercari.io/go-httpdoc"
+)
+
+func TestVarNames(t *testing.T) {
+	cases := map[string][]string{
+		"/users":                           nil,
+		"/users/{id}":                      {"id"},
+		"/users/{id:[0-9]{3}}/books/{id2}": {"id", "id2"},
+	}
+	for template, want := range cases {
+		if got := varNames(template); !reflect.DeepEqual(got, want) {
+			t.Fatalf("varNames(%q): got %q, want %q", template, got, want)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	document := &httpdoc.Document{}
+
+	r := mux.NewRouter()
+	r.Use(Middleware(document, nil))
+	r.HandleFunc("/v1/users/{id:[0-9]+}/books/{bookID}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}).Methods("GET").Name("getBook")
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/v1/users/1/books/2")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	if len(document.Entries) != 1 {
+		t.Fatalf("expect 1 entry: %d", len(document.Entries))
+	}
+	e := document.Entries[0]
+	if want := "/v1/users/{id:[0-9]+}/books/{bookID}"; e.PathTemplate != want {
+		t.Fatalf("got %q, want %q", e.PathTemplate, want)
+	}
+	if e.RouteName != "getBook" {
+		t.Fatalf("got %q, want %q", e.RouteName, "getBook")
+	}
+	if want := []httpdoc.Data{{Name: "id", Value: "1"}, {Name: "bookID", Value: "2"}}; !reflect.DeepEqual(e.PathParams, want) {
+		t.Fatalf("got %#v, want %#v", e.PathParams, want)
+	}
+}
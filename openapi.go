@@ -0,0 +1,708 @@
+package httpdoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// specMethods is list of operation keys in OpenAPI path item object.
+var specMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Spec is an OpenAPI 3 document. It's used by Validator.ConformsTo to check recorded request
+// and response conform to the operation defined in it.
+//
+// Only local references (e.g., `#/components/schemas/User`) are resolved.
+type Spec struct {
+	root       map[string]interface{}
+	operations map[string]*specOperation
+}
+
+// specOperation is an operation object with its method and path template.
+type specOperation struct {
+	method string
+	path   string
+	object map[string]interface{}
+	params []map[string]interface{}
+}
+
+// LoadSpec loads an OpenAPI document in the given file. Both JSON and YAML formats are supported.
+func LoadSpec(path string) (*Spec, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseSpec(f)
+}
+
+// ParseSpec parses an OpenAPI document from the given reader. Both JSON and YAML formats are supported.
+func ParseSpec(r io.Reader) (*Spec, error) {
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var root map[string]interface{}
+	if trimmed := bytes.TrimSpace(buf); len(trimmed) > 0 && trimmed[0] == '{' {
+		decoder := json.NewDecoder(bytes.NewReader(buf))
+		decoder.UseNumber()
+		if err := decoder.Decode(&root); err != nil {
+			return nil, fmt.Errorf("failed to decode spec: %s", err)
+		}
+	} else {
+		var v interface{}
+		if err := yaml.Unmarshal(buf, &v); err != nil {
+			return nil, fmt.Errorf("failed to decode spec: %s", err)
+		}
+		m, ok := convertYAML(v).(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("failed to decode spec: root must be an object")
+		}
+		root = m
+	}
+
+	spec := &Spec{
+		root:       root,
+		operations: make(map[string]*specOperation),
+	}
+
+	paths, _ := spec.resolve(root["paths"]).(map[string]interface{})
+	for path, v := range paths {
+		item, _ := spec.resolve(v).(map[string]interface{})
+		for _, method := range specMethods {
+			object, ok := spec.resolve(item[method]).(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			id, _ := object["operationId"].(string)
+			if id == "" {
+				continue
+			}
+			if _, ok := spec.operations[id]; ok {
+				return nil, fmt.Errorf("operationId %q is duplicated", id)
+			}
+
+			spec.operations[id] = &specOperation{
+				method: strings.ToUpper(method),
+				path:   path,
+				object: object,
+				params: spec.mergeParams(item["parameters"], object["parameters"]),
+			}
+		}
+	}
+
+	return spec, nil
+}
+
+// ConformsTo validates recorded request and response conform to the operation which has the given
+// operationId in the spec. It checks method, path, parameters, headers, body schema and status code.
+// If not, it fails the test with the JSON paths of all violations (e.g., `$.response.body.items[0].id`).
+// If operationID is empty, RecordOption.OperationID is used.
+func (v *Validator) ConformsTo(t *testing.T, spec *Spec, operationID string) {
+	if operationID == "" {
+		operationID = v.operationID
+	}
+	op, ok := spec.operations[operationID]
+	if !ok {
+		tFatalf(t, "operation %q is not found in spec", operationID)
+		return
+	}
+
+	violations := spec.validate(op, v.record)
+	if len(violations) > 0 {
+		tFatalf(t, "%s %s does not conform to operation %q:\n  %s",
+			v.record.requestMethod, v.record.requestPath, operationID, strings.Join(violations, "\n  "))
+	}
+}
+
+func (s *Spec) validate(op *specOperation, r *record) []string {
+	var violations []string
+	report := func(path, format string, args ...interface{}) {
+		violations = append(violations, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if r.requestMethod != op.method {
+		report("$.request.method", "got %s, want %s", r.requestMethod, op.method)
+	}
+
+	pathParams, ok := matchPath(op.path, r.requestPath)
+	if !ok {
+		report("$.request.path", "%q does not match %q", r.requestPath, op.path)
+	}
+
+	// Request parameters
+	cookies := (&http.Request{Header: r.requestHeaders}).Cookies()
+	defined := make(map[string]bool)
+	for _, p := range op.params {
+		name, _ := p["name"].(string)
+		in, _ := p["in"].(string)
+		path := fmt.Sprintf("$.request.%s.%s", in, name)
+
+		var value string
+		var present bool
+		switch in {
+		case "query":
+			defined[name] = true
+			_, present = r.requestParams[name]
+			value = r.requestParams.Get(name)
+		case "header":
+			_, present = r.requestHeaders[http.CanonicalHeaderKey(name)]
+			value = r.requestHeaders.Get(name)
+		case "path":
+			value, present = pathParams[name]
+		case "cookie":
+			for _, c := range cookies {
+				if c.Name == name {
+					value, present = c.Value, true
+				}
+			}
+		}
+
+		if !present {
+			if required, _ := p["required"].(bool); required {
+				report(path, "required parameter is missing")
+			}
+			continue
+		}
+
+		if schema, ok := p["schema"]; ok {
+			violations = append(violations, s.validateString(path, value, schema)...)
+		}
+	}
+
+	names := make([]string, 0, len(r.requestParams))
+	for name := range r.requestParams {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !defined[name] {
+			report("$.request.query."+name, "parameter is not defined in spec")
+		}
+	}
+
+	// Request body
+	if body, ok := s.resolve(op.object["requestBody"]).(map[string]interface{}); ok {
+		if len(r.requestBody) == 0 {
+			if required, _ := body["required"].(bool); required {
+				report("$.request.body", "required request body is missing")
+			}
+		} else {
+			violations = append(violations, s.validateContent("$.request.body", body["content"], r.requestHeaders, r.requestBody)...)
+		}
+	}
+
+	// Response
+	responses, _ := s.resolve(op.object["responses"]).(map[string]interface{})
+	response, ok := s.pickResponse(responses, r.responseStatusCode)
+	if !ok {
+		report("$.response.status", "status code %d is not defined", r.responseStatusCode)
+		return violations
+	}
+
+	headers, _ := s.resolve(response["headers"]).(map[string]interface{})
+	for _, name := range sortedKeys(headers) {
+		header, _ := s.resolve(headers[name]).(map[string]interface{})
+		path := "$.response.header." + name
+
+		values, present := r.responseHeaders[http.CanonicalHeaderKey(name)]
+		if !present || len(values) == 0 {
+			if required, _ := header["required"].(bool); required {
+				report(path, "required header is missing")
+			}
+			continue
+		}
+
+		if schema, ok := header["schema"]; ok {
+			violations = append(violations, s.validateString(path, values[0], schema)...)
+		}
+	}
+
+	if len(r.responseBody) > 0 {
+		content, ok := response["content"]
+		if !ok {
+			report("$.response.body", "body is not defined for status code %d", r.responseStatusCode)
+		} else {
+			violations = append(violations, s.validateContent("$.response.body", content, r.responseHeaders, r.responseBody)...)
+		}
+	}
+
+	return violations
+}
+
+// pickResponse picks the response object for the status code. The exact code has priority
+// over the range (e.g., `2XX`) and the range has priority over `default`.
+func (s *Spec) pickResponse(responses map[string]interface{}, code int) (map[string]interface{}, bool) {
+	keys := []string{strconv.Itoa(code), fmt.Sprintf("%dXX", code/100), fmt.Sprintf("%dxx", code/100), "default"}
+	for _, key := range keys {
+		if v, ok := responses[key]; ok {
+			response, ok := s.resolve(v).(map[string]interface{})
+			return response, ok
+		}
+	}
+	return nil, false
+}
+
+// validateContent validates body with the schema of the media type which matches Content-Type header.
+// Only JSON body is validated with schema.
+func (s *Spec) validateContent(path string, content interface{}, header http.Header, body []byte) []string {
+	contents, _ := s.resolve(content).(map[string]interface{})
+	if len(contents) == 0 {
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = ""
+	}
+
+	var mediaObject map[string]interface{}
+	for _, key := range []string{mediaType, mediaTypeRange(mediaType), "*/*"} {
+		if v, ok := contents[key]; ok {
+			mediaObject, _ = s.resolve(v).(map[string]interface{})
+			break
+		}
+	}
+
+	if mediaObject == nil {
+		return []string{fmt.Sprintf("%s: content type %q is not defined, want one of %s",
+			path, mediaType, strings.Join(sortedKeys(contents), ", "))}
+	}
+
+	schema, ok := mediaObject["schema"]
+	if !ok || !isJSONMediaType(mediaType) {
+		return nil
+	}
+
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return []string{fmt.Sprintf("%s: invalid JSON: %s", path, err)}
+	}
+
+	return s.validateSchema(path, v, schema)
+}
+
+// validateString validates a parameter or header value. Since it's always string, it's converted
+// to the type which the schema expects before validation.
+func (s *Spec) validateString(path, value string, schema interface{}) []string {
+	m, _ := s.resolve(schema).(map[string]interface{})
+
+	var v interface{} = value
+	switch schemaType(m) {
+	case "integer", "number":
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			v = json.Number(value)
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(value); err == nil {
+			v = b
+		}
+	case "array":
+		items := strings.Split(value, ",")
+		vs := make([]interface{}, 0, len(items))
+		for i, item := range items {
+			errs := s.validateString(fmt.Sprintf("%s[%d]", path, i), item, m["items"])
+			if len(errs) > 0 {
+				return errs
+			}
+			vs = append(vs, item)
+		}
+		return s.validateArrayLength(path, vs, m)
+	}
+
+	return s.validateSchema(path, v, m)
+}
+
+// validateSchema validates the value against the JSON schema and returns violations.
+func (s *Spec) validateSchema(path string, v interface{}, schema interface{}) []string {
+	m, ok := s.resolve(schema).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var violations []string
+	report := func(format string, args ...interface{}) {
+		violations = append(violations, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	for _, sub := range toSlice(m["allOf"]) {
+		violations = append(violations, s.validateSchema(path, v, sub)...)
+	}
+	if subs := toSlice(m["oneOf"]); len(subs) > 0 {
+		if matched := s.countMatched(path, v, subs); matched != 1 {
+			report("must match exactly one of %d schemas, matched %d", len(subs), matched)
+		}
+	}
+	if subs := toSlice(m["anyOf"]); len(subs) > 0 {
+		if matched := s.countMatched(path, v, subs); matched == 0 {
+			report("must match at least one of %d schemas", len(subs))
+		}
+	}
+
+	if v == nil {
+		if nullable, _ := m["nullable"].(bool); nullable || hasType(m, "null") || schemaType(m) == "" {
+			return violations
+		}
+		report("must not be null")
+		return violations
+	}
+
+	if enum := toSlice(m["enum"]); len(enum) > 0 {
+		var found bool
+		for _, e := range enum {
+			if fmt.Sprint(e) == fmt.Sprint(v) {
+				found = true
+			}
+		}
+		if !found {
+			report("%v is not one of %v", v, enum)
+		}
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if !hasType(m, "object") {
+			report("got object, want %s", schemaType(m))
+			return violations
+		}
+
+		properties, _ := s.resolve(m["properties"]).(map[string]interface{})
+		for _, name := range toSlice(m["required"]) {
+			if _, ok := v[fmt.Sprint(name)]; !ok {
+				violations = append(violations, fmt.Sprintf("%s.%s: required property is missing", path, name))
+			}
+		}
+
+		for _, name := range sortedKeys(v) {
+			if property, ok := properties[name]; ok {
+				violations = append(violations, s.validateSchema(path+"."+name, v[name], property)...)
+				continue
+			}
+
+			switch additional := s.resolve(m["additionalProperties"]).(type) {
+			case bool:
+				if !additional {
+					violations = append(violations, fmt.Sprintf("%s.%s: property is not defined in schema", path, name))
+				}
+			case map[string]interface{}:
+				violations = append(violations, s.validateSchema(path+"."+name, v[name], additional)...)
+			}
+		}
+
+	case []interface{}:
+		if !hasType(m, "array") {
+			report("got array, want %s", schemaType(m))
+			return violations
+		}
+
+		violations = append(violations, s.validateArrayLength(path, v, m)...)
+		for i, item := range v {
+			violations = append(violations, s.validateSchema(fmt.Sprintf("%s[%d]", path, i), item, m["items"])...)
+		}
+
+	case string:
+		if !hasType(m, "string") {
+			report("got string, want %s", schemaType(m))
+			return violations
+		}
+
+		if n, ok := toFloat(m["minLength"]); ok && float64(len([]rune(v))) < n {
+			report("length must be >= %v", n)
+		}
+		if n, ok := toFloat(m["maxLength"]); ok && float64(len([]rune(v))) > n {
+			report("length must be <= %v", n)
+		}
+		if pattern, ok := m["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				report("%q does not match pattern %q", v, pattern)
+			}
+		}
+		if format, ok := m["format"].(string); ok && !validFormat(format, v) {
+			report("%q is not valid %s", v, format)
+		}
+
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			report("invalid number %s", v)
+			return violations
+		}
+
+		switch {
+		case hasType(m, "number"):
+		case hasType(m, "integer"):
+			if f != math.Trunc(f) {
+				report("got number %s, want integer", v)
+			}
+		default:
+			report("got number, want %s", schemaType(m))
+			return violations
+		}
+
+		if n, ok := toFloat(m["minimum"]); ok {
+			if exclusive, _ := m["exclusiveMinimum"].(bool); (exclusive && f <= n) || f < n {
+				report("%s must be >= %v", v, n)
+			}
+		}
+		if n, ok := toFloat(m["maximum"]); ok {
+			if exclusive, _ := m["exclusiveMaximum"].(bool); (exclusive && f >= n) || f > n {
+				report("%s must be <= %v", v, n)
+			}
+		}
+
+	case bool:
+		if !hasType(m, "boolean") {
+			report("got boolean, want %s", schemaType(m))
+		}
+	}
+
+	return violations
+}
+
+func (s *Spec) countMatched(path string, v interface{}, schemas []interface{}) int {
+	var matched int
+	for _, schema := range schemas {
+		if len(s.validateSchema(path, v, schema)) == 0 {
+			matched++
+		}
+	}
+	return matched
+}
+
+func (s *Spec) validateArrayLength(path string, v []interface{}, m map[string]interface{}) []string {
+	var violations []string
+	if n, ok := toFloat(m["minItems"]); ok && float64(len(v)) < n {
+		violations = append(violations, fmt.Sprintf("%s: must have >= %v items", path, n))
+	}
+	if n, ok := toFloat(m["maxItems"]); ok && float64(len(v)) > n {
+		violations = append(violations, fmt.Sprintf("%s: must have <= %v items", path, n))
+	}
+	return violations
+}
+
+// mergeParams merges path item level parameters and operation level parameters.
+// Operation level parameters override path item level ones with the same name and location.
+func (s *Spec) mergeParams(itemParams, opParams interface{}) []map[string]interface{} {
+	var params []map[string]interface{}
+	index := make(map[string]int)
+	for _, list := range []interface{}{itemParams, opParams} {
+		for _, v := range toSlice(s.resolve(list)) {
+			p, ok := s.resolve(v).(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			key := fmt.Sprintf("%v:%v", p["in"], p["name"])
+			if i, ok := index[key]; ok {
+				params[i] = p
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// resolve follows `$ref` in the given object until it reaches non reference object.
+// If reference can not be resolved, it returns nil.
+func (s *Spec) resolve(v interface{}) interface{} {
+	for i := 0; i < 32; i++ {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return v
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			return nil
+		}
+
+		var cur interface{} = s.root
+		for _, token := range strings.Split(ref[2:], "/") {
+			token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+			if unescaped, err := url.PathUnescape(token); err == nil {
+				token = unescaped
+			}
+			obj, ok := cur.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			cur = obj[token]
+		}
+		v = cur
+	}
+	return nil
+}
+
+// matchPath matches the request path with path template like `/users/{id}` (`:id` is also allowed)
+// and returns path parameters.
+func matchPath(template, path string) (map[string]string, bool) {
+	tSegments := strings.Split(strings.Trim(template, "/"), "/")
+	pSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(tSegments) != len(pSegments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, seg := range tSegments {
+		switch {
+		case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}"):
+			params[seg[1:len(seg)-1]] = pSegments[i]
+		case strings.HasPrefix(seg, ":"):
+			params[seg[1:]] = pSegments[i]
+		case seg != pSegments[i]:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func schemaType(m map[string]interface{}) string {
+	switch t := m["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, v := range t {
+			if s, ok := v.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+
+	// If type is omitted, guess it from keywords.
+	if _, ok := m["properties"]; ok {
+		return "object"
+	}
+	if _, ok := m["items"]; ok {
+		return "array"
+	}
+	return ""
+}
+
+// hasType reports the schema accepts the given type. If type is not specified, it accepts any type.
+func hasType(m map[string]interface{}, typ string) bool {
+	switch t := m["type"].(type) {
+	case string:
+		return t == typ
+	case []interface{}:
+		for _, v := range t {
+			if v == typ {
+				return true
+			}
+		}
+		return false
+	}
+
+	st := schemaType(m)
+	return st == "" || st == typ
+}
+
+func validFormat(format, v string) bool {
+	var err error
+	switch format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, v)
+	case "date":
+		_, err = time.Parse("2006-01-02", v)
+	case "uuid":
+		if !uuidRegexp.MatchString(v) {
+			return false
+		}
+	case "email":
+		if !strings.Contains(v, "@") {
+			return false
+		}
+	case "uri":
+		var u *url.URL
+		u, err = url.Parse(v)
+		if err == nil && u.Scheme == "" {
+			return false
+		}
+	}
+	return err == nil
+}
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func mediaTypeRange(mediaType string) string {
+	if i := strings.Index(mediaType, "/"); i > 0 {
+		return mediaType[:i] + "/*"
+	}
+	return mediaType
+}
+
+func toSlice(v interface{}) []interface{} {
+	s, _ := v.([]interface{})
+	return s
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// convertYAML converts yaml decoded value into the same form as JSON decoded value.
+func convertYAML(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = convertYAML(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range v {
+			v[i] = convertYAML(val)
+		}
+		return v
+	case int:
+		return json.Number(strconv.Itoa(v))
+	case float64:
+		return json.Number(strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	return v
+}
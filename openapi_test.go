@@ -0,0 +1,311 @@
+package httpdoc
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testSpecJSON = `{
+  "openapi": "3.0.0",
+  "info": {"title": "test", "version": "1.0.0"},
+  "paths": {
+    "/v1/users/{id}": {
+      "parameters": [
+        {"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}
+      ],
+      "put": {
+        "operationId": "updateUser",
+        "parameters": [
+          {"name": "token", "in": "query", "required": true, "schema": {"type": "string", "minLength": 5}},
+          {"name": "X-Version", "in": "header", "schema": {"type": "string", "enum": ["1", "2"]}}
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {"schema": {"$ref": "#/components/schemas/User"}}
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "ok",
+            "headers": {
+              "X-Request-Id": {"required": true, "schema": {"type": "string", "format": "uuid"}}
+            },
+            "content": {
+              "application/json": {"schema": {"$ref": "#/components/schemas/User"}}
+            }
+          },
+          "4XX": {
+            "description": "client error",
+            "content": {
+              "application/json": {"schema": {"$ref": "#/components/schemas/Error"}}
+            }
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "User": {
+        "type": "object",
+        "required": ["name"],
+        "additionalProperties": false,
+        "properties": {
+          "id": {"type": "integer", "minimum": 1},
+          "name": {"type": "string"},
+          "tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2}
+        }
+      },
+      "Error": {
+        "type": "object",
+        "required": ["message"],
+        "properties": {
+          "message": {"type": "string"}
+        }
+      }
+    }
+  }
+}`
+
+const testSpecYAML = `
+openapi: 3.0.0
+info:
+  title: test
+  version: 1.0.0
+paths:
+  /v1/hello:
+    get:
+      operationId: hello
+      responses:
+        200:
+          description: ok
+          content:
+            text/plain:
+              schema:
+                type: string
+`
+
+func testConformsRecord() *record {
+	return &record{
+		requestMethod:  "PUT",
+		requestPath:    "/v1/users/10",
+		requestParams:  map[string][]string{"token": {"12345"}},
+		requestHeaders: http.Header{"Content-Type": {"application/json"}, "X-Version": {"2"}},
+		requestBody:    []byte(`{"name": "tcnksm", "tags": ["a"]}`),
+
+		responseStatusCode: http.StatusOK,
+		responseHeaders: http.Header{
+			"Content-Type": {"application/json; charset=utf-8"},
+			"X-Request-Id": {"d9b2d63d-a233-4123-847a-7d1a4c2e3f4b"},
+		},
+		responseBody: []byte(`{"id": 10, "name": "tcnksm", "tags": ["a", "b"]}`),
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	spec, err := ParseSpec(strings.NewReader(testSpecJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op, ok := spec.operations["updateUser"]
+	if !ok {
+		t.Fatalf("expect updateUser operation to be found")
+	}
+	if got, want := op.method, "PUT"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := len(op.params), 3; got != want {
+		t.Fatalf("got %d params, want %d", got, want)
+	}
+
+	spec, err = ParseSpec(strings.NewReader(testSpecYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := spec.operations["hello"]; !ok {
+		t.Fatalf("expect hello operation to be found")
+	}
+
+	if _, err := ParseSpec(strings.NewReader("{")); err == nil {
+		t.Fatalf("expect to be failed")
+	}
+}
+
+func TestSpec_Validate(t *testing.T) {
+	spec, err := ParseSpec(strings.NewReader(testSpecJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := spec.operations["updateUser"]
+
+	cases := []struct {
+		modify func(r *record)
+		want   []string
+	}{
+		{
+			func(r *record) {},
+			nil,
+		},
+		{
+			func(r *record) {
+				r.requestMethod = "POST"
+				r.requestPath = "/v1/users/abc"
+			},
+			[]string{
+				"$.request.method: got POST, want PUT",
+				`$.request.path.id: got string, want integer`,
+			},
+		},
+		{
+			func(r *record) {
+				r.requestParams = map[string][]string{"pretty": {"true"}}
+				r.requestHeaders.Set("X-Version", "3")
+			},
+			[]string{
+				"$.request.query.token: required parameter is missing",
+				`$.request.header.X-Version: 3 is not one of [1 2]`,
+				"$.request.query.pretty: parameter is not defined in spec",
+			},
+		},
+		{
+			func(r *record) {
+				r.requestBody = []byte(`{"id": 0, "email": "a@example.com"}`)
+			},
+			[]string{
+				"$.request.body.name: required property is missing",
+				"$.request.body.email: property is not defined in schema",
+				"$.request.body.id: 0 must be >= 1",
+			},
+		},
+		{
+			func(r *record) {
+				r.responseHeaders.Del("X-Request-Id")
+				r.responseBody = []byte(`{"id": 1.5, "name": "tcnksm", "tags": ["a", 2, "c"]}`)
+			},
+			[]string{
+				"$.response.header.X-Request-Id: required header is missing",
+				"$.response.body.id: got number 1.5, want integer",
+				"$.response.body.tags: must have <= 2 items",
+				"$.response.body.tags[1]: got number, want string",
+			},
+		},
+		{
+			func(r *record) {
+				r.responseStatusCode = http.StatusNotFound
+				r.responseBody = []byte(`{}`)
+			},
+			[]string{
+				"$.response.body.message: required property is missing",
+			},
+		},
+		{
+			func(r *record) {
+				r.responseStatusCode = http.StatusInternalServerError
+			},
+			[]string{
+				"$.response.status: status code 500 is not defined",
+			},
+		},
+		{
+			func(r *record) {
+				r.responseHeaders.Set("Content-Type", "text/plain")
+			},
+			[]string{
+				`$.response.body: content type "text/plain" is not defined, want one of application/json`,
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		r := testConformsRecord()
+		tc.modify(r)
+
+		got := spec.validate(op, r)
+		if len(got) != len(tc.want) {
+			t.Fatalf("#%d: got %d violations %q, want %q", i, len(got), got, tc.want)
+		}
+		for _, want := range tc.want {
+			if !containsString(got, want) {
+				t.Fatalf("#%d: expect %q to contain %q", i, got, want)
+			}
+		}
+	}
+}
+
+func TestValidator_ConformsTo(t *testing.T) {
+	defer func() { tFatalf = defaultFatalFunc }()
+
+	spec, err := ParseSpec(strings.NewReader(testSpecJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validator := newValidator()
+	validator.record = testConformsRecord()
+	validator.ConformsTo(t, spec, "updateUser")
+
+	var buf bytes.Buffer
+	tFatalf = fprintFatalFunc(&buf)
+	validator.record.responseBody = []byte(`{"name": 1}`)
+	validator.ConformsTo(t, spec, "updateUser")
+	if got, want := buf.String(), "$.response.body.name: got number, want string"; !strings.Contains(got, want) {
+		t.Fatalf("expect %q to contain %q", got, want)
+	}
+
+	buf.Reset()
+	validator.ConformsTo(t, spec, "noSuchOperation")
+	if got, want := buf.String(), "not found"; !strings.Contains(got, want) {
+		t.Fatalf("expect %q to contain %q", got, want)
+	}
+
+	// RecordOption.OperationID is used when operationId is not given.
+	buf.Reset()
+	validator.operationID = "updateUser"
+	validator.ConformsTo(t, spec, "")
+	if got, want := buf.String(), `does not conform to operation "updateUser"`; !strings.Contains(got, want) {
+		t.Fatalf("expect %q to contain %q", got, want)
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	cases := []struct {
+		template string
+		path     string
+		params   map[string]string
+		ok       bool
+	}{
+		{"/v1/users", "/v1/users", map[string]string{}, true},
+		{"/v1/users/{id}", "/v1/users/10", map[string]string{"id": "10"}, true},
+		{"/v1/users/:id/items/{item}", "/v1/users/10/items/3", map[string]string{"id": "10", "item": "3"}, true},
+		{"/v1/users/{id}", "/v1/users", nil, false},
+		{"/v1/users/{id}", "/v2/users/10", nil, false},
+	}
+
+	for _, tc := range cases {
+		params, ok := matchPath(tc.template, tc.path)
+		if ok != tc.ok {
+			t.Fatalf("%s %s: got %v, want %v", tc.template, tc.path, ok, tc.ok)
+		}
+		if ok && len(params) != len(tc.params) {
+			t.Fatalf("%s %s: got %v, want %v", tc.template, tc.path, params, tc.params)
+		}
+		for k, v := range tc.params {
+			if params[k] != v {
+				t.Fatalf("%s %s: got %v, want %v", tc.template, tc.path, params, tc.params)
+			}
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package httpdoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// postmanSchema is JSON schema URL of Postman Collection Format v2.1.
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanFolder   `json:"item"`
+	Variable []postmanVariable `json:"variable"`
+}
+
+type postmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+type postmanFolder struct {
+	Name string        `json:"name"`
+	Item []postmanItem `json:"item"`
+}
+
+type postmanItem struct {
+	Name     string            `json:"name"`
+	Request  postmanRequest    `json:"request"`
+	Response []postmanResponse `json:"response"`
+}
+
+type postmanRequest struct {
+	Method      string          `json:"method"`
+	Header      []postmanHeader `json:"header"`
+	Body        *postmanBody    `json:"body,omitempty"`
+	URL         postmanURL      `json:"url"`
+	Description string          `json:"description,omitempty"`
+}
+
+type postmanHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanBody struct {
+	Mode string `json:"mode"`
+	Raw  string `json:"raw"`
+}
+
+type postmanURL struct {
+	Raw   string            `json:"raw"`
+	Host  []string          `json:"host"`
+	Path  []string          `json:"path"`
+	Query []postmanVariable `json:"query,omitempty"`
+}
+
+type postmanVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanResponse struct {
+	Name            string          `json:"name"`
+	OriginalRequest postmanRequest  `json:"originalRequest"`
+	Status          string          `json:"status"`
+	Code            int             `json:"code"`
+	Header          []postmanHeader `json:"header"`
+	Body            string          `json:"body"`
+}
+
+// WritePostman writes recorded entries as Postman Collection (v2.1). Entries are grouped into
+// folders by path prefix and entries which have the same method and path are merged into one
+// request with multiple example responses.
+//
+// The base URL is set as `baseUrl` collection variable. Secret values (see Document.Secrets) are
+// replaced with collection variables whose values are empty.
+func (d *Document) WritePostman(w io.Writer) error {
+	collection := postmanCollection{
+		Info: postmanInfo{
+			Name:   d.Name,
+			Schema: postmanSchema,
+		},
+		Item: []postmanFolder{},
+		Variable: []postmanVariable{
+			{Key: "baseUrl", Value: d.baseURL()},
+		},
+	}
+
+	folders := make(map[string]int)
+	secrets := make(map[string]bool)
+	for _, group := range groupEntries(d.Entries) {
+		request := d.postmanRequest(&group[0], secrets)
+
+		item := postmanItem{
+			Name:     group[0].Method + " " + group[0].Path,
+			Request:  request,
+			Response: make([]postmanResponse, 0, len(group)),
+		}
+		for _, e := range group {
+			headers := make([]postmanHeader, 0, len(e.ResponseHeaders))
+			for _, h := range e.ResponseHeaders {
+				headers = append(headers, postmanHeader{Key: h.Name, Value: fmt.Sprint(h.Value)})
+			}
+
+			item.Response = append(item.Response, postmanResponse{
+				Name:            fmt.Sprintf("[%d] %s %s", e.ResponseStatusCode, e.Method, e.Path),
+				OriginalRequest: d.postmanRequest(&e, secrets),
+				Status:          http.StatusText(e.ResponseStatusCode),
+				Code:            e.ResponseStatusCode,
+				Header:          headers,
+				Body:            e.ResponseExample,
+			})
+		}
+
+		prefix := pathPrefix(group[0].Path)
+		i, ok := folders[prefix]
+		if !ok {
+			i = len(collection.Item)
+			folders[prefix] = i
+			collection.Item = append(collection.Item, postmanFolder{Name: prefix})
+		}
+		collection.Item[i].Item = append(collection.Item[i].Item, item)
+	}
+
+	for _, name := range sortedSet(secrets) {
+		collection.Variable = append(collection.Variable, postmanVariable{Key: name, Value: ""})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(&collection)
+}
+
+// postmanRequest converts entry to Postman request. Secret variable names used in the request
+// are added to secrets.
+func (d *Document) postmanRequest(e *Entry, secrets map[string]bool) postmanRequest {
+	value := func(name string, v interface{}) string {
+		if d.isSecret(name) {
+			secrets[variableName(name)] = true
+			return "{{" + variableName(name) + "}}"
+		}
+		return fmt.Sprint(v)
+	}
+
+	headers := make([]postmanHeader, 0, len(e.RequestHeaders))
+	for _, h := range e.requestHeaders() {
+		headers = append(headers, postmanHeader{Key: h.Name, Value: value(h.Name, h.Value)})
+	}
+
+	var query []postmanVariable
+	var rawQuery []string
+	for _, p := range e.requestParams() {
+		v := value(p.Name, p.Value)
+		query = append(query, postmanVariable{Key: p.Name, Value: v})
+		if !d.isSecret(p.Name) {
+			v = url.QueryEscape(v)
+		}
+		rawQuery = append(rawQuery, url.QueryEscape(p.Name)+"="+v)
+	}
+
+	raw := "{{baseUrl}}" + e.Path
+	if len(rawQuery) > 0 {
+		raw += "?" + strings.Join(rawQuery, "&")
+	}
+
+	request := postmanRequest{
+		Method: e.Method,
+		Header: headers,
+		URL: postmanURL{
+			Raw:   raw,
+			Host:  []string{"{{baseUrl}}"},
+			Path:  strings.Split(strings.Trim(e.Path, "/"), "/"),
+			Query: query,
+		},
+		Description: e.Description,
+	}
+
+	if e.RequestExample != "" {
+		request.Body = &postmanBody{Mode: "raw", Raw: e.RequestExample}
+	}
+	return request
+}
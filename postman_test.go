@@ -0,0 +1,137 @@
+package httpdoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestDocument_WritePostman(t *testing.T) {
+	doc := &Document{
+		Name:    "Example API",
+		BaseURL: "https://api.example.com/",
+		Entries: testExportEntries,
+	}
+
+	var buf bytes.Buffer
+	if err := doc.WritePostman(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got postmanCollection
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Info.Schema != postmanSchema {
+		t.Fatalf("got %q, want %q", got.Info.Schema, postmanSchema)
+	}
+
+	if got, want := len(got.Item), 2; got != want {
+		t.Fatalf("got %d folders, want %d", got, want)
+	}
+	folder := got.Item[0]
+	if got, want := folder.Name, "v1/user"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := len(folder.Item), 2; got != want {
+		t.Fatalf("got %d items, want %d", got, want)
+	}
+
+	item := folder.Item[0]
+	if got, want := len(item.Response), 2; got != want {
+		t.Fatalf("got %d responses, want %d", got, want)
+	}
+	if got, want := item.Response[1].Code, 400; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if got, want := item.Request.URL.Raw, "{{baseUrl}}/v1/user?pretty=true&token={{token}}"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := item.Request.Body.Raw, `{"name": "tcnksm"}`; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	want := []postmanVariable{
+		{Key: "baseUrl", Value: "https://api.example.com"},
+		{Key: "token", Value: ""},
+	}
+	if len(got.Variable) != len(want) {
+		t.Fatalf("got %v, want %v", got.Variable, want)
+	}
+	for i := range want {
+		if got.Variable[i] != want[i] {
+			t.Fatalf("got %v, want %v", got.Variable, want)
+		}
+	}
+}
+
+func TestDocument_WritePostman_Record(t *testing.T) {
+	// Values which handler actually received are exported. Documented parameters and headers
+	// which are not sent are not exported.
+	entry := Entry{
+		Method: "GET",
+		Path:   "/v1/user",
+		RequestParams: []Data{
+			{Name: "pretty", Value: IsUUID()},
+			{Name: "page", Value: "1"},
+		},
+		RequestHeaders: []Data{
+			{Name: "X-Version", Value: "1"},
+			{Name: "X-Request-Id", Value: "abc"},
+		},
+		ResponseStatusCode: http.StatusOK,
+		record: &record{
+			requestParams:  map[string][]string{"pretty": {"true"}},
+			requestHeaders: http.Header{"X-Version": {"2"}},
+		},
+	}
+	doc := &Document{Entries: []Entry{entry}}
+
+	var buf bytes.Buffer
+	if err := doc.WritePostman(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got postmanCollection
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	request := got.Item[0].Item[0].Request
+	if got, want := request.URL.Raw, "{{baseUrl}}/v1/user?pretty=true"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got, want := len(request.Header), 1; got != want {
+		t.Fatalf("got %d headers, want %d", got, want)
+	}
+	if got, want := request.Header[0].Value, "2"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestDocument_WritePostman_Proto(t *testing.T) {
+	doc := &Document{Entries: recordProtoEntries(t)}
+
+	var buf bytes.Buffer
+	if err := doc.WritePostman(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var got postmanCollection
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	// The json example decoded from protocol buffer encoded body is sent as json.
+	request := got.Item[0].Item[0].Request
+	if got, want := request.Body.Raw, "{\n  \"id\": 7089\n}\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for _, h := range request.Header {
+		if h.Key == "Content-Type" && h.Value != "application/json" {
+			t.Fatalf("got %q, want %q", h.Value, "application/json")
+		}
+	}
+}
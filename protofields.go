@@ -0,0 +1,239 @@
+package httpdoc
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// LoadFileDescriptorSet loads the FileDescriptorSet file which protoc generates with
+// `--descriptor_set_out` option. It's used for Document.ProtoFiles. To use comments in .proto
+// files as descriptions, generate it with `--include_source_info` and `--include_imports` options.
+//
+//   $ protoc --include_source_info --include_imports --descriptor_set_out=api.pb api.proto
+//
+func LoadFileDescriptorSet(path string) (*protoregistry.Files, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var set descriptorpb.FileDescriptorSet
+	if err := protov2.Unmarshal(buf, &set); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal FileDescriptorSet %s: %s", path, err)
+	}
+
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load FileDescriptorSet %s: %s", path, err)
+	}
+	return files, nil
+}
+
+// messageDescriptor returns the descriptor of the message. Descriptor in Document.ProtoFiles is
+// preferred because descriptors registered by generated code don't have comments.
+func (d *Document) messageDescriptor(md protoreflect.MessageDescriptor) protoreflect.MessageDescriptor {
+	files := d.ProtoFiles
+	if files == nil {
+		files = protoregistry.GlobalFiles
+	}
+	if desc, err := files.FindDescriptorByName(md.FullName()); err == nil {
+		if found, ok := desc.(protoreflect.MessageDescriptor); ok {
+			return found
+		}
+	}
+	return md
+}
+
+// protoFields returns every field of the decoded message including fields of nested messages.
+// Field names are Go field names (e.g., `Setting.Email`) which are same as TestCase.Target. Leading
+// comments of fields are used as descriptions.
+func (d *Document) protoFields(m protov2.Message) []Data {
+	var fields []Data
+	var walk func(md protoreflect.MessageDescriptor, m protoreflect.Message, prefix string, visited map[protoreflect.FullName]bool)
+	walk = func(md protoreflect.MessageDescriptor, m protoreflect.Message, prefix string, visited map[protoreflect.FullName]bool) {
+		if visited[md.FullName()] {
+			return
+		}
+		visited[md.FullName()] = true
+		defer delete(visited, md.FullName())
+
+		for i := 0; i < md.Fields().Len(); i++ {
+			fd := md.Fields().Get(i)
+			name := prefix + goCamelCase(string(fd.Name()))
+
+			// Value of messages, lists and maps are not written, they are documented by their fields.
+			data := Data{
+				Name:        name,
+				Value:       "",
+				Description: protoComment(fd),
+				Schema:      protoSchema(fd),
+			}
+
+			// Descriptor in Document.ProtoFiles is not same as the descriptor of the message, so
+			// the field is looked up by name.
+			var field protoreflect.FieldDescriptor
+			if m != nil {
+				field = m.Descriptor().Fields().ByName(fd.Name())
+			}
+			if field != nil && fd.Message() == nil && !fd.IsList() && !fd.IsMap() {
+				data.Value = protoValue(field, m.Get(field))
+			}
+			fields = append(fields, data)
+
+			switch {
+			case fd.IsMap():
+				if value := fd.MapValue(); value.Message() != nil {
+					walk(value.Message(), nil, name+"[*].", visited)
+				}
+			case fd.Message() != nil && fd.IsList():
+				walk(fd.Message(), nil, name+"[*].", visited)
+			case fd.Message() != nil:
+				var nested protoreflect.Message
+				if field != nil && m.Has(field) {
+					nested = m.Get(field).Message()
+				}
+				walk(fd.Message(), nested, name+".", visited)
+			}
+		}
+	}
+
+	pm := m.ProtoReflect()
+	walk(d.messageDescriptor(pm.Descriptor()), pm, "", make(map[protoreflect.FullName]bool))
+	return fields
+}
+
+// mergeFields merges fields documented by the validator and fields of the body (e.g., fields of the
+// protocol buffer message). Descriptions of the body are used if the validator doesn't have them.
+// Schemas of the body are used as the base and TestCase.Schema overrides them.
+func mergeFields(validated, fields []Data) []Data {
+	merged := make([]Data, len(validated))
+	copy(merged, validated)
+	for _, f := range fields {
+		var contain bool
+		for i := range merged {
+			if merged[i].Name != f.Name {
+				continue
+			}
+			contain = true
+			if merged[i].Description == "" {
+				merged[i].Description = f.Description
+			}
+			merged[i].Schema = f.Schema.merge(merged[i].explicit)
+		}
+		if !contain {
+			merged = append(merged, f)
+		}
+	}
+	return merged
+}
+
+// protoSchema returns the schema of the field. Like fields of structs, Type is JSON type in protocol
+// buffer JSON mapping (e.g., `integer` or `array of string`) and Format is proto type (e.g., `int32`),
+// message name or enum name.
+func protoSchema(fd protoreflect.FieldDescriptor) Schema {
+	s := Schema{
+		Required: fd.Cardinality() == protoreflect.Required,
+	}
+	if fd.IsMap() {
+		s.Type, s.Format = "object", fmt.Sprintf("map<%s, %s>", protoMapTypeName(fd.MapKey()), protoMapTypeName(fd.MapValue()))
+		return s
+	}
+	s.Type, s.Format = protoJSONType(fd), protoKindName(fd)
+	if fd.IsList() {
+		s.Type = "array of " + s.Type
+	}
+	if fd.Cardinality() == protoreflect.Optional && fd.HasPresence() && fd.ContainingOneof() == nil && fd.Message() == nil {
+		s.Constraints = append(s.Constraints, "optional")
+	}
+	if oneof := fd.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+		s.Constraints = append(s.Constraints, "oneof "+string(oneof.Name()))
+	}
+	return s
+}
+
+// protoJSONType returns JSON type of the field value in protocol buffer JSON mapping. 64-bit integers
+// and bytes are strings in the mapping.
+func protoJSONType(fd protoreflect.FieldDescriptor) string {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return "boolean"
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return "integer"
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return "number"
+	case protoreflect.EnumKind:
+		return "enum"
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return "object"
+	}
+	return "string"
+}
+
+// protoKindName returns proto type name of the field value, e.g., `int32`. Message and enum fields
+// have their full names and bytes fields have `byte` like struct fields. It's empty for string and
+// bool fields since their JSON types are enough.
+func protoKindName(fd protoreflect.FieldDescriptor) string {
+	switch {
+	case fd.Message() != nil:
+		return string(fd.Message().FullName())
+	case fd.Enum() != nil:
+		return string(fd.Enum().FullName())
+	case fd.Kind() == protoreflect.StringKind || fd.Kind() == protoreflect.BoolKind:
+		return ""
+	case fd.Kind() == protoreflect.BytesKind:
+		return "byte"
+	}
+	return fd.Kind().String()
+}
+
+// protoMapTypeName returns proto type name of the map key or value, e.g., `string`.
+func protoMapTypeName(fd protoreflect.FieldDescriptor) string {
+	if fd.Message() != nil || fd.Enum() != nil {
+		return protoKindName(fd)
+	}
+	return fd.Kind().String()
+}
+
+// protoComment returns the leading comment of the field in .proto file. Lines are joined with space.
+func protoComment(fd protoreflect.FieldDescriptor) string {
+	loc := fd.ParentFile().SourceLocations().ByDescriptor(fd)
+	return strings.Join(strings.Fields(loc.LeadingComments), " ")
+}
+
+// goCamelCase converts the proto field name to Go field name in the same way as protoc-gen-go,
+// e.g., `user_id` is `UserId`.
+func goCamelCase(s string) string {
+	var b []byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '_' && i == 0:
+			b = append(b, 'X')
+		case c == '_' && i+1 < len(s) && isASCIILower(s[i+1]):
+			// Skip the underscore and capitalize the next letter.
+		case '0' <= c && c <= '9':
+			b = append(b, c)
+		default:
+			if isASCIILower(c) {
+				c -= 'a' - 'A'
+			}
+			b = append(b, c)
+			for ; i+1 < len(s) && isASCIILower(s[i+1]); i++ {
+				b = append(b, s[i+1])
+			}
+		}
+	}
+	return string(b)
+}
+
+func isASCIILower(c byte) bool {
+	return 'a' <= c && c <= 'z'
+}
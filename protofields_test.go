@@ -0,0 +1,184 @@
+package httpdoc
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestGoCamelCase(t *testing.T) {
+	cases := map[string]string{
+		"id":           "Id",
+		"user_id":      "UserId",
+		"created_at_2": "CreatedAt_2",
+		"_hidden":      "XHidden",
+		"URL":          "URL",
+	}
+	for in, want := range cases {
+		if got := goCamelCase(in); got != want {
+			t.Fatalf("goCamelCase(%q): got %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestProtoSchema(t *testing.T) {
+	value := (&structpb.Value{}).ProtoReflect().Descriptor().Fields()
+	cases := []struct {
+		field protoreflect.FieldDescriptor
+		want  Schema
+	}{
+		{
+			field: (&timestamppb.Timestamp{}).ProtoReflect().Descriptor().Fields().ByName("seconds"),
+			want:  Schema{Type: "string", Format: "int64"},
+		},
+		{
+			field: (&timestamppb.Timestamp{}).ProtoReflect().Descriptor().Fields().ByName("nanos"),
+			want:  Schema{Type: "integer", Format: "int32"},
+		},
+		{
+			field: value.ByName("number_value"),
+			want:  Schema{Type: "number", Format: "double", Constraints: []string{"oneof kind"}},
+		},
+		{
+			field: value.ByName("null_value"),
+			want:  Schema{Type: "enum", Format: "google.protobuf.NullValue", Constraints: []string{"oneof kind"}},
+		},
+		{
+			field: (&structpb.ListValue{}).ProtoReflect().Descriptor().Fields().ByName("values"),
+			want:  Schema{Type: "array of object", Format: "google.protobuf.Value"},
+		},
+		{
+			field: (&structpb.Struct{}).ProtoReflect().Descriptor().Fields().ByName("fields"),
+			want:  Schema{Type: "object", Format: "map<string, google.protobuf.Value>"},
+		},
+	}
+	for _, tc := range cases {
+		if got := protoSchema(tc.field); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("protoSchema(%s): got %#v, want %#v", tc.field.FullName(), got, tc.want)
+		}
+	}
+}
+
+// writeFileDescriptorSet writes FileDescriptorSet of message.proto with the given leading comments
+// of UserProtoResponse fields.
+func writeFileDescriptorSet(t *testing.T, path string, comments map[int32]string) {
+	md := proto.MessageV2(&UserProtoResponse{}).ProtoReflect().Descriptor()
+	file := protodesc.ToFileDescriptorProto(md.ParentFile())
+
+	info := &descriptorpb.SourceCodeInfo{}
+	for field, comment := range comments {
+		info.Location = append(info.Location, &descriptorpb.SourceCodeInfo_Location{
+			// message_type = 4, UserProtoResponse = 1, field = 2
+			Path:            []int32{4, int32(md.Index()), 2, field},
+			Span:            []int32{0, 0, 0},
+			LeadingComments: protov2.String(comment),
+		})
+	}
+	file.SourceCodeInfo = info
+
+	buf, err := protov2.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{file}})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ioutil.WriteFile(path, buf, 0644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestRecord_ProtoFields(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpdoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "message.pb")
+	writeFileDescriptorSet(t, path, map[int32]string{
+		0: " User ID\n",
+		1: " User name.\n Displayed on profile.\n",
+	})
+	files, err := LoadFileDescriptorSet(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	doc := &Document{ProtoFiles: files}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, _ := proto.Marshal(&UserProtoResponse{
+			Id:      12345,
+			Name:    "tcnksm",
+			Setting: &UserProtoResponse_Setting{Email: "tcnksm@example.com"},
+		})
+		w.Write(buf)
+	})
+
+	ts := httptest.NewServer(Record(handler, doc, &RecordOption{
+		WithValidate: func(validator *Validator) {
+			validator.ResponseBody(t, []TestCase{
+				NewTestCase("Id", int32(12345), ""),
+				NewTestCase("Name", "tcnksm", "Name of user"),
+				{Target: "Setting.Email", Expected: "tcnksm@example.com", Schema: &Schema{Format: "email"}},
+			}, &UserProtoResponse{})
+		},
+		WithProtoBuffer: &ProtoBufferOption{
+			ResponseUnmarshaler: &UserProtoResponse{},
+		},
+	}))
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/v1/user")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	var got []string
+	for _, f := range doc.Entries[0].ResponseFields {
+		got = append(got, strings.Join([]string{f.Name, f.Type, f.Format, f.Description}, "|"))
+	}
+	want := []string{
+		"Active|boolean||",
+		"Id|integer|int32|User ID",
+		"Name|string||Name of user",
+		"Setting|object|httpdoc.UserProtoResponse.Setting|",
+		"Setting.Email|string|email|",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	if got := doc.Entries[0].ResponseFields[4].Value; got != "tcnksm@example.com" {
+		t.Fatalf("expect actual value to be documented: %#v", got)
+	}
+}
+
+func TestLoadFileDescriptorSet_Error(t *testing.T) {
+	if _, err := LoadFileDescriptorSet("not-found.pb"); err == nil {
+		t.Fatal("expect to fail")
+	}
+
+	f, err := ioutil.TempFile("", "httpdoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("invalid")
+	f.Close()
+
+	if _, err := LoadFileDescriptorSet(f.Name()); err == nil || !strings.Contains(err.Error(), "failed to unmarshal") {
+		t.Fatalf("expect to fail to unmarshal: %v", err)
+	}
+}
@@ -0,0 +1,218 @@
+package httpdoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"reflect"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// protoJSONUnmarshalFunc unmarshals json encoded body of protocol buffer endpoints. Messages are
+// unmarshaled in protocol buffer JSON mapping.
+var protoJSONUnmarshalFunc = func(data []byte, v interface{}) error {
+	if m, ok := v.(protov2.Message); ok {
+		return protojson.Unmarshal(data, m)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// protoMessage decodes protocol buffer encoded body. New message is created for every body if the
+// message type is given. Otherwise, the unmarshaler is reset and reused.
+type protoMessage struct {
+	new         func() protov2.Message
+	unmarshaler proto.Unmarshaler
+}
+
+func (p protoMessage) valid() bool {
+	return p.new != nil || p.unmarshaler != nil
+}
+
+// decode unmarshals protocol buffer encoded data and returns the message and its example in
+// indented json format. The message is nil if it's not protocol buffer message.
+func (p protoMessage) decode(data []byte) (string, protov2.Message, error) {
+	if p.new == nil {
+		example, err := protoExample(p.unmarshaler, data)
+		if m, ok := p.unmarshaler.(proto.Message); ok {
+			return example, proto.MessageV2(m), err
+		}
+		return example, nil, err
+	}
+
+	m := p.new()
+	if err := protov2.Unmarshal(data, m); err != nil {
+		return "", nil, err
+	}
+	example, err := protoJSON(m)
+	return example, m, err
+}
+
+// decodeJSON unmarshals json encoded data into new message.
+func (p protoMessage) decodeJSON(data []byte) (protov2.Message, error) {
+	if p.new == nil {
+		return nil, fmt.Errorf("message type is not given")
+	}
+	m := p.new()
+	if err := protojson.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// protoJSON encodes the message into indented json format in protocol buffer JSON mapping.
+func protoJSON(m protov2.Message) (string, error) {
+	buf, err := protojson.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+
+	// protojson output is unstable by design, so it's indented again.
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf, "", "  "); err != nil {
+		return "", err
+	}
+	out.WriteByte('\n')
+	return out.String(), nil
+}
+
+// protoMessages returns decoders of request and response bodies. Message factories are preferred to
+// message types and unmarshalers.
+func (d *Document) protoMessages(opt *ProtoBufferOption) (protoMessage, protoMessage, error) {
+	if opt == nil {
+		return protoMessage{}, protoMessage{}, nil
+	}
+
+	request := protoMessage{unmarshaler: opt.RequestUnmarshaler}
+	response := protoMessage{unmarshaler: opt.ResponseUnmarshaler}
+
+	var err error
+	if request.new, err = d.messageFactory(opt.RequestMessage, opt.RequestType); err != nil {
+		return request, response, err
+	}
+	if response.new, err = d.messageFactory(opt.ResponseMessage, opt.ResponseType); err != nil {
+		return request, response, err
+	}
+	return request, response, nil
+}
+
+// messageFactory returns the function which creates new message. The message type is resolved from
+// registered types or Document.ProtoFiles. nil is returned if neither factory nor name is given.
+func (d *Document) messageFactory(factory func() proto.Message, name string) (func() protov2.Message, error) {
+	if factory != nil {
+		return func() protov2.Message {
+			return proto.MessageV2(factory())
+		}, nil
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	if mt, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(name)); err == nil {
+		return func() protov2.Message {
+			return mt.New().Interface()
+		}, nil
+	}
+
+	if d.ProtoFiles != nil {
+		if desc, err := d.ProtoFiles.FindDescriptorByName(protoreflect.FullName(name)); err == nil {
+			if md, ok := desc.(protoreflect.MessageDescriptor); ok {
+				return func() protov2.Message {
+					return dynamicpb.NewMessage(md)
+				}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("message type %q is not found", name)
+}
+
+// protoMediaTypes is list of media types of protocol buffer encoded bodies. `application/proto` is
+// used by Connect protocol.
+var protoMediaTypes = []string{
+	"application/protobuf",
+	"application/x-protobuf",
+	"application/x-google-protobuf",
+	"application/vnd.google.protobuf",
+	"application/proto",
+	"application/octet-stream",
+}
+
+// isProtoContentType reports whether the body of the content type is protocol buffer encoded.
+// Bodies without Content-Type are protocol buffer encoded. Other bodies (e.g., json or text/plain
+// error messages written by http.Error) are not decoded as protocol buffer.
+func isProtoContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range protoMediaTypes {
+		if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// protoField returns the value of the field of the message. Field name is Go field name (e.g., `UserId`)
+// or name in .proto file (e.g., `user_id`). It's used to inspect messages which don't have Go struct
+// fields, e.g., dynamic messages.
+func protoField(m protoreflect.Message, name string) reflect.Value {
+	fields := m.Descriptor().Fields()
+	fd := fields.ByName(protoreflect.Name(name))
+	for i := 0; fd == nil && i < fields.Len(); i++ {
+		if f := fields.Get(i); goCamelCase(string(f.Name())) == name || f.JSONName() == name {
+			fd = f
+		}
+	}
+	if fd == nil {
+		return reflect.Value{}
+	}
+	if fd.HasPresence() && !m.Has(fd) {
+		return reflect.Value{}
+	}
+	return reflect.ValueOf(protoValue(fd, m.Get(fd)))
+}
+
+// protoValue converts the value of the field into Go value. Lists are slices, maps are maps keyed by
+// string and enums are their names.
+func protoValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch {
+	case fd.IsList():
+		list := v.List()
+		values := make([]interface{}, list.Len())
+		for i := range values {
+			values[i] = protoSingularValue(fd, list.Get(i))
+		}
+		return values
+	case fd.IsMap():
+		values := make(map[string]interface{})
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			values[k.String()] = protoSingularValue(fd.MapValue(), v)
+			return true
+		})
+		return values
+	}
+	return protoSingularValue(fd, v)
+}
+
+func protoSingularValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return v.Message().Interface()
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return int32(v.Enum())
+	}
+	return v.Interface()
+}
@@ -0,0 +1,244 @@
+package httpdoc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// itemFiles returns descriptors of `dyn.Item` message which doesn't have Go type.
+func itemFiles(t *testing.T) *protoregistry.Files {
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    protov2.String("dyn.proto"),
+		Package: protov2.String("dyn"),
+		Syntax:  protov2.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: protov2.String("Item"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{
+					Name:     protov2.String("name"),
+					JsonName: protov2.String("name"),
+					Number:   protov2.Int32(1),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				},
+				{
+					Name:     protov2.String("tags"),
+					JsonName: protov2.String("tags"),
+					Number:   protov2.Int32(2),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+				},
+			},
+		}},
+	}
+
+	files, err := protodesc.NewFiles(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{file}})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return files
+}
+
+func TestRecord_ProtoMessage_Fresh(t *testing.T) {
+	doc := &Document{}
+	responses := []*UserProtoResponse{
+		{Id: 1, Setting: &UserProtoResponse_Setting{Email: "first@example.com"}},
+		{Id: 2},
+	}
+	var i int
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, _ := proto.Marshal(responses[i])
+		i++
+		w.Write(buf)
+	})
+
+	ts := httptest.NewServer(Record(handler, doc, &RecordOption{
+		WithProtoBuffer: &ProtoBufferOption{
+			ResponseMessage: func() proto.Message { return &UserProtoResponse{} },
+		},
+	}))
+	defer ts.Close()
+
+	for range responses {
+		res, err := http.Get(ts.URL + "/v1/user")
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		res.Body.Close()
+	}
+
+	if got := doc.Entries[1].ResponseExample; strings.Contains(got, "first@example.com") {
+		t.Fatalf("expect the previous message not to be merged: %s", got)
+	}
+	if want := "\"id\": 2"; !strings.Contains(doc.Entries[1].ResponseExample, want) {
+		t.Fatalf("expect %q to contain %q", doc.Entries[1].ResponseExample, want)
+	}
+}
+
+func TestRecord_ProtoMessage_Unmarshaler(t *testing.T) {
+	doc := &Document{}
+	responses := []*UserProtoResponse{
+		{Id: 1, Setting: &UserProtoResponse_Setting{Email: "first@example.com"}},
+		{Id: 2},
+	}
+	var i int
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, _ := proto.Marshal(responses[i])
+		i++
+		w.Write(buf)
+	})
+
+	// The unmarshaler is reused for every request, so it must be reset.
+	ts := httptest.NewServer(Record(handler, doc, &RecordOption{
+		WithProtoBuffer: &ProtoBufferOption{
+			ResponseUnmarshaler: &UserProtoResponse{},
+		},
+	}))
+	defer ts.Close()
+
+	for range responses {
+		res, err := http.Get(ts.URL + "/v1/user")
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		res.Body.Close()
+	}
+
+	if got := doc.Entries[1].ResponseExample; strings.Contains(got, "first@example.com") {
+		t.Fatalf("expect the previous message not to be merged: %s", got)
+	}
+}
+
+func TestRecord_ProtoMessage_TextError(t *testing.T) {
+	doc := &Document{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "user not found", http.StatusNotFound)
+	})
+
+	ts := httptest.NewServer(Record(handler, doc, &RecordOption{
+		WithProtoBuffer: &ProtoBufferOption{
+			ResponseMessage: func() proto.Message { return &UserProtoResponse{} },
+		},
+	}))
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/v1/user")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	// Error message which isn't protocol buffer is written as it is.
+	entry := doc.Entries[0]
+	if got, want := entry.ResponseExample, "user not found\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if len(entry.ResponseFields) != 0 {
+		t.Fatalf("expect no response fields: %#v", entry.ResponseFields)
+	}
+}
+
+func TestRecord_ProtoMessage_Type(t *testing.T) {
+	files := itemFiles(t)
+	doc := &Document{ProtoFiles: files}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		desc, _ := files.FindDescriptorByName("dyn.Item")
+		m := dynamicpb.NewMessage(desc.(protoreflect.MessageDescriptor))
+		if err := protojson.Unmarshal([]byte(`{"name": "apple", "tags": ["fruit", "red"]}`), m); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		if r.Header.Get("Accept") == "application/json" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"name": "apple", "tags": ["fruit", "red"]}`))
+			return
+		}
+		buf, _ := protov2.Marshal(m)
+		w.Header().Set("Content-Type", "application/protobuf")
+		w.Write(buf)
+	})
+
+	ts := httptest.NewServer(Record(handler, doc, &RecordOption{
+		WithValidate: func(validator *Validator) {
+			validator.ResponseBody(t, []TestCase{
+				NewTestCase("Name", "apple", "Item name"),
+				NewTestCase("Tags[*]", AnyString(), "Item tags"),
+				NewTestCase("Tags[1]", "red", ""),
+			}, nil)
+		},
+		WithProtoBuffer: &ProtoBufferOption{
+			ResponseType: "dyn.Item",
+		},
+	}))
+	defer ts.Close()
+
+	for _, accept := range []string{"application/protobuf", "application/json"} {
+		req, _ := http.NewRequest("GET", ts.URL+"/v1/item", nil)
+		req.Header.Set("Accept", accept)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		res.Body.Close()
+	}
+
+	for _, e := range doc.Entries {
+		var names []string
+		for _, f := range e.ResponseFields {
+			names = append(names, f.Name+"|"+f.Type)
+		}
+		if want := []string{"Name|string", "Tags|array of string", "Tags[*]|", "Tags[1]|"}; !reflect.DeepEqual(names, want) {
+			t.Fatalf("got %q, want %q", names, want)
+		}
+		if want := `"name": "apple"`; !strings.Contains(e.ResponseExample, want) {
+			t.Fatalf("expect %q to contain %q", e.ResponseExample, want)
+		}
+	}
+}
+
+func TestDocument_MessageFactory_NotFound(t *testing.T) {
+	doc := &Document{}
+	if _, err := doc.messageFactory(nil, "dyn.Unknown"); err == nil || !strings.Contains(err.Error(), `"dyn.Unknown" is not found`) {
+		t.Fatalf("expect to fail: %v", err)
+	}
+
+	// Registered type is resolved without Document.ProtoFiles.
+	f, err := doc.messageFactory(nil, "httpdoc.UserProtoRequest")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if f() == f() {
+		t.Fatal("expect new message to be created every time")
+	}
+}
+
+func TestIsProtoContentType(t *testing.T) {
+	cases := map[string]bool{
+		"":                                true,
+		"application/protobuf":            true,
+		"application/x-protobuf":          true,
+		"application/octet-stream":        true,
+		"text/plain; charset=utf-8":       false,
+		"application/json":                false,
+		"application/json; charset=utf-8": false,
+		"application/vnd.api+json":        false,
+	}
+	for contentType, want := range cases {
+		if got := isProtoContentType(contentType); got != want {
+			t.Fatalf("%q: got %v, want %v", contentType, got, want)
+		}
+	}
+}
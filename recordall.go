@@ -0,0 +1,294 @@
+package httpdoc
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// routePattern is a pattern of RecordAll options and the handler which records requests with the option.
+type routePattern struct {
+	pattern string
+	method  string
+
+	// path is the path of the pattern without `{$}` and `{name...}`.
+	path string
+
+	// prefix is true if the path ends with a slash. Like http.ServeMux, such pattern matches
+	// all paths under it.
+	prefix bool
+
+	// exact is true if the pattern ends with `{$}`. It matches only the path which ends with a slash.
+	exact bool
+
+	// rest is the name of the last `{name...}` segment which matches the rest of the path.
+	rest string
+
+	handler http.Handler
+}
+
+// RecordAll is a http middleware which records every request the given http handler (e.g., the
+// http.ServeMux of the service) receives. Unlike Record, routes don't need to be wrapped one by one.
+//
+// The option of the request is chosen from opts by pattern. A pattern is a path optionally
+// preceded by a method (e.g., `GET /v1/users/{id}`). Like http.ServeMux, `GET` also matches `HEAD`
+// requests and patterns with other methods match only the method. Path parameters are written as
+// `{name}` or `:name`. Like http.ServeMux, a path which ends with a slash (e.g., `/v1/`) matches
+// all paths under it, `{$}` at the end (e.g., `/v1/{$}`) matches only the path itself and
+// `{name...}` at the end (e.g., `/files/{path...}`) matches the rest of the path. If multiple
+// patterns match, the most specific one is used: patterns which don't match the rest of the path
+// are preferred, then patterns which have more literal segments, then patterns with method.
+// Requests which no pattern matches are recorded without option. It panics if a pattern is not
+// supported, e.g., patterns with host (`example.com/v1/`) or wildcards in the middle of the path.
+//
+// If the pattern of the request doesn't end with a slash and the option has no RouteResolver, the
+// pattern path is used as the path template (`{name...}` is written as `{name}`) and path
+// parameters are documented. Such patterns are also added to Document.Routes to report routes
+// never exercised by tests.
+//
+//   handler := httpdoc.RecordAll(mux, document, map[string]*httpdoc.RecordOption{
+//       "GET /v1/users/{id}": {Summary: "Get a user"},
+//       "/v1/admin/":         {Tags: []string{"admin"}},
+//   })
+//
+func RecordAll(next http.Handler, document *Document, opts map[string]*RecordOption) http.Handler {
+	keys := make([]string, 0, len(opts))
+	for pattern := range opts {
+		keys = append(keys, pattern)
+	}
+	sort.Strings(keys)
+
+	patterns := make([]*routePattern, 0, len(opts))
+	for _, pattern := range keys {
+		p := parseRoutePattern(pattern)
+		if err := p.validate(); err != nil {
+			panic(err)
+		}
+		opt := opts[pattern]
+
+		o := RecordOption{}
+		if opt != nil {
+			o = *opt
+		}
+		if !p.prefix {
+			if o.RouteResolver == nil {
+				o.RouteResolver = p.resolveRoute
+			}
+			document.addRoute(pattern)
+		}
+		p.handler = Record(next, document, &o)
+		patterns = append(patterns, p)
+	}
+	sort.Sort(bySpecificity(patterns))
+
+	fallback := Record(next, document, nil)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, p := range patterns {
+			if p.match(r) {
+				p.handler.ServeHTTP(w, r)
+				return
+			}
+		}
+		fallback.ServeHTTP(w, r)
+	})
+}
+
+// Middleware returns a http middleware which records every request in the document.
+// See RecordAll for opts.
+//
+//   http.ListenAndServe(":8080", document.Middleware(nil)(mux))
+//
+func (d *Document) Middleware(opts map[string]*RecordOption) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return RecordAll(next, d, opts)
+	}
+}
+
+// addRoute adds the pattern to Document.Routes if it's not registered yet.
+func (d *Document) addRoute(pattern string) {
+	for _, route := range d.Routes {
+		if route == pattern {
+			return
+		}
+	}
+	d.Routes = append(d.Routes, pattern)
+}
+
+// parseRoutePattern parses the pattern like `GET /v1/users/{id}` or `/v1/`.
+func parseRoutePattern(pattern string) *routePattern {
+	p := &routePattern{
+		pattern: pattern,
+		path:    strings.TrimSpace(pattern),
+	}
+	if i := strings.IndexAny(p.path, " \t"); i >= 0 {
+		p.method, p.path = strings.ToUpper(p.path[:i]), strings.TrimSpace(p.path[i+1:])
+	}
+
+	i := strings.LastIndex(p.path, "/")
+	switch last := p.path[i+1:]; {
+	case last == "{$}":
+		p.path, p.exact = p.path[:i+1], true
+	case strings.HasPrefix(last, "{") && strings.HasSuffix(last, "...}"):
+		p.path, p.rest = p.path[:i+1], last[1:len(last)-4]
+	default:
+		p.prefix = strings.HasSuffix(p.path, "/")
+	}
+	return p
+}
+
+// validate returns error if the pattern is not supported.
+func (p *routePattern) validate() error {
+	if !strings.HasPrefix(p.path, "/") {
+		return fmt.Errorf("httpdoc: pattern %q is not supported: path must start with a slash", p.pattern)
+	}
+	if p.rest != "" && !validVariableName(p.rest) {
+		return fmt.Errorf("httpdoc: pattern %q is not supported: invalid wildcard name %q", p.pattern, p.rest)
+	}
+	for _, seg := range p.segments() {
+		if name, ok := pathVariable(seg); ok {
+			if !validVariableName(name) {
+				return fmt.Errorf("httpdoc: pattern %q is not supported: invalid wildcard %q", p.pattern, seg)
+			}
+			continue
+		}
+		if strings.ContainsAny(seg, "{}") {
+			return fmt.Errorf("httpdoc: pattern %q is not supported: invalid segment %q", p.pattern, seg)
+		}
+	}
+	return nil
+}
+
+// validVariableName reports whether the name of path parameter is supported. `{$}` and `{name...}`
+// are supported only at the end of the pattern.
+func validVariableName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, "{}$.")
+}
+
+func (p *routePattern) match(r *http.Request) bool {
+	if !p.matchMethod(r.Method) {
+		return false
+	}
+	_, ok := p.params(r.URL.Path)
+	return ok
+}
+
+// matchMethod reports whether the pattern matches the method. Like http.ServeMux, `GET` also
+// matches `HEAD`.
+func (p *routePattern) matchMethod(method string) bool {
+	return p.method == "" || p.method == method || (p.method == http.MethodGet && method == http.MethodHead)
+}
+
+// params returns path parameters in the order of the pattern.
+func (p *routePattern) params(path string) ([]Data, bool) {
+	template := p.path
+	var rest string
+	switch {
+	case p.exact:
+		if !strings.HasSuffix(path, "/") {
+			return nil, false
+		}
+	case p.prefix || p.rest != "":
+		if !strings.HasPrefix(path, "/") {
+			return nil, false
+		}
+		// Match the pattern with leading segments of the path.
+		n := len(p.segments())
+		segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+		if len(segments) < n {
+			return nil, false
+		}
+		path, rest = "/"+strings.Join(segments[:n], "/"), strings.Join(segments[n:], "/")
+		template = strings.TrimSuffix(template, "/")
+	}
+
+	values, ok := matchPath(template, path)
+	if !ok {
+		return nil, false
+	}
+
+	var params []Data
+	for _, seg := range p.segments() {
+		if name, ok := pathVariable(seg); ok {
+			params = append(params, Data{Name: name, Value: values[name]})
+		}
+	}
+	if p.rest != "" {
+		params = append(params, Data{Name: p.rest, Value: rest})
+	}
+	return params, true
+}
+
+// template returns the path template of the pattern. `{$}` is removed and `{name...}` is written
+// as `{name}`.
+func (p *routePattern) template() string {
+	if p.rest != "" {
+		return p.path + "{" + p.rest + "}"
+	}
+	return p.path
+}
+
+func (p *routePattern) resolveRoute(r *http.Request) *Route {
+	params, ok := p.params(r.URL.Path)
+	if !ok {
+		return nil
+	}
+	return &Route{
+		PathTemplate: p.template(),
+		Params:       params,
+	}
+}
+
+// segments returns path segments of the pattern without `{$}` and `{name...}`. The root pattern
+// `/` has no segment.
+func (p *routePattern) segments() []string {
+	path := strings.Trim(p.path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// literals returns the number of segments which are not path parameters.
+func (p *routePattern) literals() int {
+	var n int
+	for _, seg := range p.segments() {
+		if _, ok := pathVariable(seg); !ok {
+			n++
+		}
+	}
+	return n
+}
+
+// pathVariable returns the name of path parameter segment like `{id}` or `:id`.
+func pathVariable(seg string) (string, bool) {
+	switch {
+	case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}"):
+		return seg[1 : len(seg)-1], true
+	case strings.HasPrefix(seg, ":"):
+		return seg[1:], true
+	}
+	return "", false
+}
+
+// bySpecificity sorts patterns from the most specific one.
+type bySpecificity []*routePattern
+
+func (s bySpecificity) Len() int      { return len(s) }
+func (s bySpecificity) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s bySpecificity) Less(i, j int) bool {
+	a, b := s[i], s[j]
+	if wa, wb := a.prefix || a.rest != "", b.prefix || b.rest != ""; wa != wb {
+		return !wa
+	}
+	if la, lb := a.literals(), b.literals(); la != lb {
+		return la > lb
+	}
+	if sa, sb := len(a.segments()), len(b.segments()); sa != sb {
+		return sa > sb
+	}
+	if (a.method != "") != (b.method != "") {
+		return a.method != ""
+	}
+	return a.pattern < b.pattern
+}
@@ -0,0 +1,222 @@
+package httpdoc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestRecordAll(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", testHandler)
+
+	document := &Document{}
+	handler := RecordAll(mux, document, map[string]*RecordOption{
+		"GET /v1/users/{id}":    {Summary: "Get a user"},
+		"/v1/users/me":          {Summary: "Get me"},
+		"/v1/users/{id}/books/": {Tags: []string{"book"}},
+		"/v1/":                  {Tags: []string{"v1"}},
+	})
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	requests := []struct {
+		method string
+		path   string
+	}{
+		{"GET", "/v1/users/1"},
+		{"GET", "/v1/users/me"},
+		{"DELETE", "/v1/users/1"},
+		{"GET", "/v1/users/1/books/2"},
+		{"GET", "/v2/users"},
+	}
+	for _, req := range requests {
+		r, _ := http.NewRequest(req.method, ts.URL+req.path, nil)
+		res, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		res.Body.Close()
+	}
+
+	if len(document.Entries) != len(requests) {
+		t.Fatalf("expect %d entries: %d", len(requests), len(document.Entries))
+	}
+
+	cases := []struct {
+		summary      string
+		tags         []string
+		pathTemplate string
+		pathParams   []Data
+	}{
+		{"Get a user", nil, "/v1/users/{id}", []Data{{Name: "id", Value: "1"}}},
+		{"Get me", nil, "/v1/users/me", nil},
+		{"", []string{"v1"}, "", nil},
+		{"", []string{"book"}, "", nil},
+		{"", nil, "", nil},
+	}
+	for i, tc := range cases {
+		e := document.Entries[i]
+		if e.Summary != tc.summary {
+			t.Fatalf("#%d: got summary %q, want %q", i, e.Summary, tc.summary)
+		}
+		if !reflect.DeepEqual(e.Tags, tc.tags) {
+			t.Fatalf("#%d: got tags %v, want %v", i, e.Tags, tc.tags)
+		}
+		if e.PathTemplate != tc.pathTemplate {
+			t.Fatalf("#%d: got path template %q, want %q", i, e.PathTemplate, tc.pathTemplate)
+		}
+		if !reflect.DeepEqual(e.PathParams, tc.pathParams) {
+			t.Fatalf("#%d: got path params %#v, want %#v", i, e.PathParams, tc.pathParams)
+		}
+	}
+}
+
+func TestDocument_Middleware(t *testing.T) {
+	document := &Document{}
+	handler := document.Middleware(nil)(http.HandlerFunc(testHandler))
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/v1/hello")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	if len(document.Entries) != 1 {
+		t.Fatalf("expect 1 entry: %d", len(document.Entries))
+	}
+	if got, want := document.Entries[0].Path, "/v1/hello"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestRoutePattern_Params(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		want    []Data
+		ok      bool
+	}{
+		{"/", "/", nil, true},
+		{"/", "/v1/users", nil, true},
+		{"/v1/users", "/v1/users", nil, true},
+		{"/v1/users", "/v1/users/1", nil, false},
+		{"/v1/users/", "/v1/users/1", nil, true},
+		{"/v1/users/{id}", "/v1/users/1", []Data{{Name: "id", Value: "1"}}, true},
+		{"/v1/users/:id/books/{bookID}", "/v1/users/1/books/2", []Data{{Name: "id", Value: "1"}, {Name: "bookID", Value: "2"}}, true},
+		{"/v1/users/{id}/", "/v1/users/1/books/2", []Data{{Name: "id", Value: "1"}}, true},
+		{"/v1/users/{id}/", "/v2/users/1", nil, false},
+		{"/v1/{$}", "/v1/", nil, true},
+		{"/v1/{$}", "/v1", nil, false},
+		{"/v1/{$}", "/v1/users", nil, false},
+		{"/files/{path...}", "/files/a/b.txt", []Data{{Name: "path", Value: "a/b.txt"}}, true},
+		{"/files/{path...}", "/files/", []Data{{Name: "path", Value: ""}}, true},
+		{"/users/{id}/files/{path...}", "/users/1/files/a", []Data{{Name: "id", Value: "1"}, {Name: "path", Value: "a"}}, true},
+		{"/files/{path...}", "/images/a", nil, false},
+	}
+	for _, tc := range cases {
+		got, ok := parseRoutePattern(tc.pattern).params(tc.path)
+		if ok != tc.ok {
+			t.Fatalf("%s %s: got %v, want %v", tc.pattern, tc.path, ok, tc.ok)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s %s: got %#v, want %#v", tc.pattern, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestRecordAll_Method(t *testing.T) {
+	document := &Document{}
+	handler := RecordAll(http.HandlerFunc(testHandler), document, map[string]*RecordOption{
+		"GET /v1/users": {Summary: "List users"},
+	})
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	for _, method := range []string{"GET", "HEAD", "POST"} {
+		r, _ := http.NewRequest(method, ts.URL+"/v1/users", nil)
+		res, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		res.Body.Close()
+	}
+
+	// Like http.ServeMux, GET matches HEAD.
+	var got []string
+	for _, e := range document.Entries {
+		got = append(got, e.Method+":"+e.Summary)
+	}
+	if want := []string{"GET:List users", "HEAD:List users", "POST:"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestRecordAll_Unsupported(t *testing.T) {
+	patterns := []string{
+		"example.com/v1/",
+		"GET example.com/v1/users",
+		"/v1/{$}/users",
+		"/files/{path...}/raw",
+		"/v1/users/{}",
+		"/v1/users/id-{id}",
+	}
+	for _, pattern := range patterns {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("%s: expect to panic", pattern)
+				}
+			}()
+			RecordAll(http.HandlerFunc(testHandler), &Document{}, map[string]*RecordOption{pattern: nil})
+		}()
+	}
+}
+
+func TestRoutePattern_ResolveRoute(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		want    string
+	}{
+		{"/v1/users/{id}", "/v1/users/1", "/v1/users/{id}"},
+		{"/v1/{$}", "/v1/", "/v1/"},
+		{"GET /files/{path...}", "/files/a/b", "/files/{path}"},
+	}
+	for _, tc := range cases {
+		r, _ := http.NewRequest("GET", tc.path, nil)
+		route := parseRoutePattern(tc.pattern).resolveRoute(r)
+		if route == nil {
+			t.Fatalf("%s: expect route to be resolved", tc.pattern)
+		}
+		if route.PathTemplate != tc.want {
+			t.Fatalf("%s: got %q, want %q", tc.pattern, route.PathTemplate, tc.want)
+		}
+	}
+}
+
+func TestBySpecificity(t *testing.T) {
+	patterns := []string{"/", "/v1/", "/v1/users/{id}", "GET /v1/users/{id}", "/v1/users/me", "/v1/users/{id}/", "/v1/{$}", "/v1/users/{id}/{rest...}"}
+
+	var sorted []*routePattern
+	for _, p := range patterns {
+		sorted = append(sorted, parseRoutePattern(p))
+	}
+	sort.Sort(bySpecificity(sorted))
+
+	var got []string
+	for _, p := range sorted {
+		got = append(got, p.pattern)
+	}
+	want := []string{"/v1/users/me", "GET /v1/users/{id}", "/v1/users/{id}", "/v1/{$}", "/v1/users/{id}/", "/v1/users/{id}/{rest...}", "/v1/", "/"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
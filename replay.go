@@ -0,0 +1,322 @@
+package httpdoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+)
+
+// maxClosestEntries is the number of recorded entries listed when no entry matches the request.
+const maxClosestEntries = 5
+
+type replayHandler struct {
+	document *Document
+}
+
+// NewReplayHandler returns a http.Handler which serves recorded entries in the given document as fake
+// responses. This is useful to provide a mock server for frontend or client development based on
+// the backend tests.
+//
+// A request is matched with an entry by method and path. The path template of the entry (see
+// Entry.PathTemplate) is used if it's resolved, so the entry recorded by `/users/42` also serves
+// `/users/43`. GraphQL and JSON-RPC entries are also matched by the operation (see Entry.Operation)
+// which is parsed from the request body. Each call of JSON-RPC batch request is served by the entry
+// of its method and responses are combined in a batch response. If multiple entries are matched,
+// the one which has the most same query parameters and headers is used. If no entry matches, it
+// responds 404 with the list of the closest recorded entries.
+func NewReplayHandler(document *Document) http.Handler {
+	return &replayHandler{
+		document: document,
+	}
+}
+
+func (h *replayHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req := newReplayRequest(r)
+	if req.batch {
+		h.serveBatch(w, r, req)
+		return
+	}
+
+	entry, ok := h.match(r, req.operations())
+	if !ok {
+		h.notFound(w, r)
+		return
+	}
+
+	status := entry.ResponseStatusCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	// Use raw recorded values if exist. Otherwise (e.g., entry is not recorded by Record middleware),
+	// use values for documentation.
+	if entry.record != nil {
+		for k, v := range entry.record.responseHeaders {
+			w.Header()[k] = v
+		}
+		body := entry.record.responseBody
+		if entry.JSONRPC != nil && len(req.calls) == 1 {
+			body = req.calls[0].response(body)
+		}
+		w.WriteHeader(status)
+		w.Write(body)
+		return
+	}
+
+	for _, d := range entry.ResponseHeaders {
+		w.Header().Set(d.Name, fmt.Sprint(d.Value))
+	}
+	w.WriteHeader(status)
+	w.Write([]byte(entry.ResponseExample))
+}
+
+// serveBatch serves JSON-RPC batch request. Each call is served by the entry of its method and
+// notifications are not responded. Calls whose entry is not found are responded with error.
+func (h *replayHandler) serveBatch(w http.ResponseWriter, r *http.Request, req *replayRequest) {
+	var responses []json.RawMessage
+	for _, call := range req.calls {
+		if len(call.id) == 0 {
+			continue
+		}
+		entry, ok := h.match(r, map[string]bool{call.method: true})
+		if !ok || entry.record == nil || len(entry.record.responseBody) == 0 {
+			responses = append(responses, call.methodNotFound())
+			continue
+		}
+		responses = append(responses, call.response(entry.record.responseBody))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	json.NewEncoder(w).Encode(responses)
+}
+
+// match returns the entry which matches the request best. GraphQL and JSON-RPC entries are matched
+// only when their operations are one of the given operations of the request.
+func (h *replayHandler) match(r *http.Request, operations map[string]bool) (Entry, bool) {
+	var (
+		best      Entry
+		bestScore int
+		found     bool
+	)
+	for _, entry := range h.document.Entries {
+		if entry.Method != r.Method {
+			continue
+		}
+		if _, ok := matchPath(entry.path(), r.URL.Path); !ok {
+			continue
+		}
+		if (entry.GraphQL != nil || entry.JSONRPC != nil) && !operations[entry.Operation] {
+			continue
+		}
+
+		score := scoreData(entry.RequestParams, func(name string) (string, bool) {
+			v, ok := r.URL.Query()[name]
+			if !ok || len(v) == 0 {
+				return "", false
+			}
+			return v[0], true
+		})
+		score += scoreData(entry.RequestHeaders, func(name string) (string, bool) {
+			v, ok := r.Header[http.CanonicalHeaderKey(name)]
+			if !ok || len(v) == 0 {
+				return "", false
+			}
+			return v[0], true
+		})
+
+		// Entry which is recorded by the same path is preferred to the one matched by the template.
+		if entry.Path == r.URL.Path {
+			score++
+		}
+
+		if !found || score > bestScore {
+			best, bestScore, found = entry, score, true
+		}
+	}
+	return best, found
+}
+
+// replayRequest is the request which the replay handler serves. Its body is parsed to match GraphQL
+// and JSON-RPC entries by operation.
+type replayRequest struct {
+	graphQL *GraphQLOperation
+	calls   []replayCall
+	batch   bool
+}
+
+// replayCall is a call of JSON-RPC request.
+type replayCall struct {
+	method string
+	id     json.RawMessage
+}
+
+// newReplayRequest parses the request. The request body is restored to be read again.
+func newReplayRequest(r *http.Request) *replayRequest {
+	var body []byte
+	if r.Body != nil {
+		body, _ = ioutil.ReadAll(r.Body)
+		r.Body.Close()
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	rec := &record{
+		requestMethod:  r.Method,
+		requestParams:  r.URL.Query(),
+		requestHeaders: r.Header,
+		requestBody:    body,
+	}
+
+	req := &replayRequest{}
+	req.graphQL, _, _ = graphQLOperation(rec)
+
+	messages, batch := jsonRPCMessages(body)
+	for _, raw := range messages {
+		var call struct {
+			Method string          `json:"method"`
+			ID     json.RawMessage `json:"id"`
+		}
+		if err := json.Unmarshal(raw, &call); err != nil || call.Method == "" {
+			continue
+		}
+		req.calls = append(req.calls, replayCall{method: call.Method, id: call.ID})
+	}
+	req.batch = batch && len(req.calls) > 0
+	return req
+}
+
+// operations returns operations of the request, i.e., GraphQL operation or JSON-RPC methods.
+func (req *replayRequest) operations() map[string]bool {
+	operations := make(map[string]bool)
+	if req.graphQL != nil {
+		operations[req.graphQL.String()] = true
+	}
+	for _, call := range req.calls {
+		operations[call.method] = true
+	}
+	return operations
+}
+
+// response returns the recorded response object whose id is replaced with the id of the call.
+func (c replayCall) response(recorded []byte) []byte {
+	var res map[string]json.RawMessage
+	if len(c.id) == 0 || json.Unmarshal(recorded, &res) != nil {
+		return recorded
+	}
+	res["id"] = c.id
+	b, err := json.Marshal(res)
+	if err != nil {
+		return recorded
+	}
+	return b
+}
+
+// methodNotFound returns the error response of the call whose method is not recorded.
+func (c replayCall) methodNotFound() json.RawMessage {
+	b, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      c.id,
+		"error": map[string]interface{}{
+			"code":    -32601,
+			"message": "Method not found",
+		},
+	})
+	return b
+}
+
+// scoreData scores how much the request values are same as recorded values.
+// Same value (or value matched by Matcher) increases and different value decreases the score.
+func scoreData(data []Data, lookup func(name string) (string, bool)) int {
+	var score int
+	for _, d := range data {
+		v, ok := lookup(d.Name)
+		if !ok {
+			continue
+		}
+		if m, ok := d.Value.(Matcher); ok && m.Match(v) || v == fmt.Sprint(d.Value) {
+			score++
+		} else {
+			score--
+		}
+	}
+	return score
+}
+
+// notFound responds the list of the closest recorded entries to help to find what's wrong.
+func (h *replayHandler) notFound(w http.ResponseWriter, r *http.Request) {
+	target := r.Method + " " + r.URL.Path
+	candidates := make(byDistance, 0, len(h.document.Entries))
+	for _, entry := range h.document.Entries {
+		candidates = append(candidates, candidate{
+			entry:    entry,
+			distance: levenshtein(target, entry.Method+" "+entry.path()),
+		})
+	}
+	sort.Stable(candidates)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "httpdoc: no recorded entry matches %s\n", target)
+	if len(candidates) > 0 {
+		fmt.Fprintf(&b, "\nClosest recorded entries:\n")
+	}
+	for i, c := range candidates {
+		if i == maxClosestEntries {
+			break
+		}
+		fmt.Fprintf(&b, "  [%d] %s\n", c.entry.ResponseStatusCode, c.entry.endpoint())
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusNotFound)
+	b.WriteTo(w)
+}
+
+type candidate struct {
+	entry    Entry
+	distance int
+}
+
+type byDistance []candidate
+
+func (d byDistance) Len() int           { return len(d) }
+func (d byDistance) Less(i, j int) bool { return d[i].distance < d[j].distance }
+func (d byDistance) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+
+// levenshtein returns edit distance between 2 strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
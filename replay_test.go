@@ -0,0 +1,205 @@
+package httpdoc
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReplayHandler(t *testing.T) {
+	document := &Document{
+		Entries: []Entry{
+			{
+				Method: "GET",
+				Path:   "/v1/users/{id}",
+				RequestParams: []Data{
+					{Name: "pretty", Value: "true"},
+				},
+				ResponseStatusCode: http.StatusOK,
+				ResponseHeaders: []Data{
+					{Name: "Content-Type", Value: "application/json"},
+				},
+				ResponseExample: `{"id": 1, "pretty": true}`,
+			},
+			{
+				Method: "GET",
+				Path:   "/v1/users/{id}",
+				RequestParams: []Data{
+					{Name: "pretty", Value: "false"},
+				},
+				ResponseStatusCode: http.StatusOK,
+				ResponseExample:    `{"id": 1}`,
+			},
+			{
+				Method:             "DELETE",
+				Path:               "/v1/users/1",
+				ResponseStatusCode: http.StatusNoContent,
+			},
+		},
+	}
+
+	testServer := httptest.NewServer(NewReplayHandler(document))
+	defer testServer.Close()
+
+	cases := []struct {
+		method     string
+		path       string
+		statusCode int
+		body       string
+	}{
+		{"GET", "/v1/users/1?pretty=true", http.StatusOK, `{"id": 1, "pretty": true}`},
+		{"GET", "/v1/users/2?pretty=false", http.StatusOK, `{"id": 1}`},
+		{"GET", "/v1/users/2", http.StatusOK, `{"id": 1, "pretty": true}`},
+		{"DELETE", "/v1/users/1", http.StatusNoContent, ""},
+		{"DELETE", "/v1/users/2", http.StatusNotFound, "[204] DELETE /v1/users/1"},
+		{"POST", "/v1/users", http.StatusNotFound, "no recorded entry matches POST /v1/users"},
+	}
+
+	for _, tc := range cases {
+		req, err := http.NewRequest(tc.method, testServer.URL+tc.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+
+		if res.StatusCode != tc.statusCode {
+			t.Fatalf("%s %s: got %d, want %d", tc.method, tc.path, res.StatusCode, tc.statusCode)
+		}
+		if !strings.Contains(string(body), tc.body) {
+			t.Fatalf("%s %s: expect %q to contain %q", tc.method, tc.path, body, tc.body)
+		}
+	}
+}
+
+func TestReplayHandler_Recorded(t *testing.T) {
+	document := &Document{}
+	recordServer := httptest.NewServer(Record(http.HandlerFunc(testHandlerProto), document, nil))
+	if _, err := http.Get(recordServer.URL + "/v1/hello_proto"); err != nil {
+		t.Fatal(err)
+	}
+	recordServer.Close()
+
+	replayServer := httptest.NewServer(NewReplayHandler(document))
+	defer replayServer.Close()
+
+	res, err := http.Get(replayServer.URL + "/v1/hello_proto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, _ := ioutil.ReadAll(res.Body)
+	var got UserProtoResponse
+	if err := got.Unmarshal(body); err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "tcnksm" {
+		t.Fatalf("got %q, want %q", got.Name, "tcnksm")
+	}
+	if got, want := res.Header.Get("Content-Type"), "application/protobuf"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestReplayHandler_PathTemplate(t *testing.T) {
+	document := &Document{
+		Entries: []Entry{
+			{Method: "GET", Path: "/v1/users/42", PathTemplate: "/v1/users/{id}", ResponseExample: "user"},
+			{Method: "GET", Path: "/v1/users/me", PathTemplate: "/v1/users/{id}", ResponseExample: "me"},
+		},
+	}
+
+	testServer := httptest.NewServer(NewReplayHandler(document))
+	defer testServer.Close()
+
+	for path, want := range map[string]string{"/v1/users/43": "user", "/v1/users/me": "me"} {
+		res, err := http.Get(testServer.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if string(body) != want {
+			t.Fatalf("%s: got %q, want %q", path, body, want)
+		}
+	}
+}
+
+func TestReplayHandler_Operation(t *testing.T) {
+	jsonHeader := http.Header{"Content-Type": []string{"application/json"}}
+	document := &Document{
+		Entries: []Entry{
+			{
+				Method: "POST", Path: "/graphql", Operation: "query GetUser", GraphQL: &GraphQLOperation{Type: "query", Name: "GetUser"},
+				record: &record{responseHeaders: jsonHeader, responseBody: []byte(`{"data":{"user":{}}}`)},
+			},
+			{
+				Method: "POST", Path: "/graphql", Operation: "mutation DeleteUser", GraphQL: &GraphQLOperation{Type: "mutation", Name: "DeleteUser"},
+				record: &record{responseHeaders: jsonHeader, responseBody: []byte(`{"data":{"deleteUser":true}}`)},
+			},
+			{
+				Method: "POST", Path: "/rpc", Operation: "user.get", JSONRPC: &JSONRPCCall{Method: "user.get", ID: "1"},
+				record: &record{responseHeaders: jsonHeader, responseBody: []byte(`{"jsonrpc":"2.0","id":1,"result":"tcnksm"}`)},
+			},
+			{
+				Method: "POST", Path: "/rpc", Operation: "user.count", JSONRPC: &JSONRPCCall{Method: "user.count", ID: "2"},
+				record: &record{responseHeaders: jsonHeader, responseBody: []byte(`{"jsonrpc":"2.0","id":2,"result":10}`)},
+			},
+		},
+	}
+
+	testServer := httptest.NewServer(NewReplayHandler(document))
+	defer testServer.Close()
+
+	cases := []struct {
+		path string
+		body string
+		want string
+	}{
+		{"/graphql", `{"query": "mutation DeleteUser { deleteUser(id: 1) }"}`, `{"data":{"deleteUser":true}}`},
+		{"/graphql", `{"query": "query GetUser { user { name } }"}`, `{"data":{"user":{}}}`},
+		{"/rpc", `{"jsonrpc": "2.0", "id": "a", "method": "user.count"}`, `{"id":"a","jsonrpc":"2.0","result":10}`},
+		{
+			"/rpc",
+			`[{"jsonrpc": "2.0", "id": 3, "method": "user.get"}, {"jsonrpc": "2.0", "method": "user.touch"}, {"jsonrpc": "2.0", "id": 4, "method": "user.delete"}]`,
+			`[{"id":3,"jsonrpc":"2.0","result":"tcnksm"},{"error":{"code":-32601,"message":"Method not found"},"id":4,"jsonrpc":"2.0"}]`,
+		},
+	}
+	for _, tc := range cases {
+		res, err := http.Post(testServer.URL+tc.path, "application/json", strings.NewReader(tc.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if got := strings.TrimSpace(string(body)); got != tc.want {
+			t.Fatalf("%s: got %q, want %q", tc.body, got, tc.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"GET /v1/user", "GET /v1/user", 0},
+		{"GET /v1/user", "GET /v1/users", 1},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tc := range cases {
+		if got := levenshtein(tc.a, tc.b); got != tc.want {
+			t.Fatalf("levenshtein(%q, %q): got %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
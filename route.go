@@ -0,0 +1,87 @@
+package httpdoc
+
+import (
+	"net/http"
+	"testing"
+)
+
+// Route is the route of the router which handled the request. Router adapters (e.g., chidoc and
+// muxdoc packages) resolve it from the router.
+type Route struct {
+	// PathTemplate is the path pattern of the route in the syntax of the router, e.g.,
+	// `/v1/users/{id}` for chi and gorilla/mux or `/v1/users/:id` for gin and echo. It's not
+	// normalized between routers: documentation and NewReplayHandler use it as it is (regexp
+	// constraints like `{id:[0-9]+}` are not checked by replay) and exporters (e.g., WritePostman)
+	// use the request path instead.
+	PathTemplate string
+
+	// Name is the name of the route. It's empty if the router doesn't name routes.
+	Name string
+
+	// Params are path parameters which the router extracted, e.g., `id`. Params are written in
+	// the order of the path template.
+	Params []Data
+}
+
+// RouteResolver returns the route which handled the request. It's called after the handler returns,
+// so routers which set the matched route to the request context while routing can resolve it.
+// It returns nil if the route is not found.
+type RouteResolver func(r *http.Request) *Route
+
+// path returns the path template of the entry if it's resolved, otherwise the request path.
+func (e Entry) path() string {
+	if e.PathTemplate != "" {
+		return e.PathTemplate
+	}
+	return e.Path
+}
+
+// PathParams validates path parameters are expected or not. Path parameters are available only
+// when RecordOption.RouteResolver resolves the route.
+func (v *Validator) PathParams(t *testing.T, cases []TestCase) {
+	for _, tc := range cases {
+		data := Data{
+			Name:        tc.Target,
+			Value:       tc.Expected,
+			Description: tc.Description,
+		}
+		v.pathParams = append(v.pathParams, data)
+
+		actual, ok := pathParam(v.record.pathParams, tc.Target)
+		if !ok {
+			tFatalf(t, "path parameter %q is not found", tc.Target)
+			continue
+		}
+		pickAssertFunc(&tc, v)(t, tc.Expected, actual, tc.Description)
+	}
+}
+
+func pathParam(params []Data, name string) (interface{}, bool) {
+	for _, p := range params {
+		if p.Name == name {
+			return p.Value, true
+		}
+	}
+	return nil, false
+}
+
+// mergeParams merges path parameters documented by the validator and parameters of the route.
+// Parameters are ordered as the route and documented ones are preferred.
+func mergeParams(validated, params []Data) []Data {
+	var merged []Data
+	for _, p := range params {
+		for _, v := range validated {
+			if v.Name == p.Name {
+				p = v
+				break
+			}
+		}
+		merged = append(merged, p)
+	}
+	for _, v := range validated {
+		if _, ok := pathParam(params, v.Name); !ok {
+			merged = append(merged, v)
+		}
+	}
+	return merged
+}
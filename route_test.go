@@ -0,0 +1,102 @@
+package httpdoc
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecord_RouteResolver(t *testing.T) {
+	doc := &Document{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+
+	ts := httptest.NewServer(Record(handler, doc, &RecordOption{
+		RouteResolver: func(r *http.Request) *Route {
+			segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+			if len(segments) != 5 {
+				return nil
+			}
+			return &Route{
+				PathTemplate: "/v1/users/{id}/books/{book_id}",
+				Name:         "getBook",
+				Params: []Data{
+					{Name: "id", Value: segments[2]},
+					{Name: "book_id", Value: segments[4]},
+				},
+			}
+		},
+		WithValidate: func(validator *Validator) {
+			if validator.record.pathParams == nil {
+				return
+			}
+			validator.PathParams(t, []TestCase{
+				NewTestCase("book_id", AnyString(), "Book ID"),
+			})
+		},
+	}))
+	defer ts.Close()
+
+	for _, path := range []string{"/v1/users/1/books/2", "/v1/users/3/books/4", "/v1/users"} {
+		res, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		res.Body.Close()
+	}
+
+	e := doc.Entries[0]
+	if e.PathTemplate != "/v1/users/{id}/books/{book_id}" || e.RouteName != "getBook" || e.Path != "/v1/users/1/books/2" {
+		t.Fatalf("got %q %q %q", e.PathTemplate, e.RouteName, e.Path)
+	}
+	if want := []Data{
+		{Name: "id", Value: "1"},
+		{Name: "book_id", Value: AnyString(), Description: "Book ID"},
+	}; !reflect.DeepEqual(describeMatchers(e.PathParams), describeMatchers(want)) {
+		t.Fatalf("got %#v, want %#v", e.PathParams, want)
+	}
+	if e := doc.Entries[2]; e.PathTemplate != "" || e.PathParams != nil {
+		t.Fatalf("expect route not to be resolved: %#v", e)
+	}
+
+	if got := len(groupEntries(doc.Entries)); got != 2 {
+		t.Fatalf("expect entries to be grouped by path template: %d groups", got)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.generate(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, want := range []string{
+		"## [200] GET /v1/users/{id}/books/{book_id}",
+		"Route: `getBook`",
+		"Path parameters\n\n| Name  | Value  | Description |\n| ----- | :----- | :--------- |\n| id | 1 |  |\n| book_id | any string | Book ID |",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expect %q to contain %q", buf.String(), want)
+		}
+	}
+}
+
+func TestValidator_PathParams_NotFound(t *testing.T) {
+	var buf bytes.Buffer
+	tFatalf = fprintFatalFunc(&buf)
+	defer func() {
+		tFatalf = defaultFatalFunc
+	}()
+
+	v := newValidator()
+	v.record.pathParams = []Data{{Name: "id", Value: "1"}}
+	v.PathParams(t, []TestCase{
+		NewTestCase("id", "1", "User ID"),
+		NewTestCase("name", "tcnksm", "User name"),
+	})
+
+	if want := `path parameter "name" is not found`; !strings.Contains(buf.String(), want) {
+		t.Fatalf("expect %q to contain %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,418 @@
+package httpdoc
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	// RPC protocols which map HTTP requests to RPCs.
+	ProtocolGRPCGateway = "grpc-gateway"
+	ProtocolConnect     = "connect"
+	ProtocolTwirp       = "twirp"
+)
+
+// RPC is the proto RPC which the endpoint is mapped to. Normally, you don't need to modify this.
+// All fields are exported just for templating.
+type RPC struct {
+	// Protocol is the protocol which maps the HTTP request to the RPC, e.g., `grpc-gateway`.
+	Protocol string
+
+	// FullName is full name of the method (e.g., `example.v1.UserService.GetUser`). Service and
+	// Method are its service full name and method name.
+	FullName string
+	Service  string
+	Method   string
+
+	// InputType and OutputType are full names of input and output messages of the method.
+	InputType  string
+	OutputType string
+
+	// File is the path of .proto file which defines the service (e.g., `example/v1/user.proto`).
+	// Line is the line of the method definition. It's 0 if the file doesn't have source info.
+	File string
+	Line int
+
+	// SourceURL is the link to the method definition. See Document.ProtoSourceURL.
+	SourceURL string
+
+	// requestType and responseType are full names of messages of request and response bodies.
+	// They are different from input and output types if grpc-gateway maps a field to the body.
+	requestType  string
+	responseType string
+
+	// description is leading comments of the method.
+	description string
+}
+
+// RPCAdapter resolves the RPC which the HTTP request is mapped to. Services are looked up in the
+// given files. It returns nil if the request is not mapped to any RPC.
+type RPCAdapter func(r *http.Request, files *protoregistry.Files) *RPC
+
+// GRPCGatewayAdapter returns RPCAdapter for grpc-gateway. The request is matched with
+// `google.api.http` options of methods. Body mapping (`body` and `response_body`) is respected.
+// If multiple bindings match (e.g., `/v1/users/{id}` and `/v1/users/me`), the most specific one is
+// used: bindings which have more literal segments are preferred and ties are broken by the method
+// full name.
+func GRPCGatewayAdapter() RPCAdapter {
+	return func(r *http.Request, files *protoregistry.Files) *RPC {
+		var (
+			best        protoreflect.MethodDescriptor
+			bestRule    httpRule
+			bestLiteral int
+		)
+		files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+			services := fd.Services()
+			for i := 0; i < services.Len(); i++ {
+				methods := services.Get(i).Methods()
+				for j := 0; j < methods.Len(); j++ {
+					md := methods.Get(j)
+					for _, rule := range httpRules(md) {
+						if rule.method != r.Method || !matchPathTemplate(rule.path, r.URL.Path) {
+							continue
+						}
+						literal := literalSegments(rule.path)
+						if best == nil || literal > bestLiteral ||
+							literal == bestLiteral && md.FullName() < best.FullName() {
+							best, bestRule, bestLiteral = md, rule, literal
+						}
+					}
+				}
+			}
+			return true
+		})
+		if best == nil {
+			return nil
+		}
+
+		rpc := newRPC(ProtocolGRPCGateway, best)
+		rpc.requestType = bodyType(best.Input(), bestRule.body, "")
+		rpc.responseType = bodyType(best.Output(), bestRule.responseBody, "*")
+		return rpc
+	}
+}
+
+// literalSegments returns the number of segments of the path template which are not variables
+// or wildcards. The verb is counted as a literal segment.
+func literalSegments(template string) int {
+	template, verb := splitVerb(template)
+	var n int
+	if verb != "" {
+		n++
+	}
+	for _, seg := range templateSegments(template) {
+		if seg != "*" && seg != "**" {
+			n++
+		}
+	}
+	return n
+}
+
+// ConnectAdapter returns RPCAdapter for Connect. The path is `/<service full name>/<method>`.
+func ConnectAdapter() RPCAdapter {
+	return func(r *http.Request, files *protoregistry.Files) *RPC {
+		return findRPC(ProtocolConnect, files, r.URL.Path)
+	}
+}
+
+// TwirpAdapter returns RPCAdapter for Twirp. The path is `<prefix>/<service full name>/<method>`.
+// If prefix is empty, `/twirp` is used.
+func TwirpAdapter(prefix string) RPCAdapter {
+	if prefix == "" {
+		prefix = "/twirp"
+	}
+	prefix = "/" + strings.Trim(prefix, "/")
+	return func(r *http.Request, files *protoregistry.Files) *RPC {
+		if !strings.HasPrefix(r.URL.Path, prefix+"/") {
+			return nil
+		}
+		return findRPC(ProtocolTwirp, files, strings.TrimPrefix(r.URL.Path, prefix))
+	}
+}
+
+// findRPC returns the RPC of the path `/<service full name>/<method>`.
+func findRPC(protocol string, files *protoregistry.Files, path string) *RPC {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) != 2 {
+		return nil
+	}
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(parts[0]))
+	if err != nil {
+		return nil
+	}
+	sd, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil
+	}
+	md := sd.Methods().ByName(protoreflect.Name(parts[1]))
+	if md == nil {
+		return nil
+	}
+	return newRPC(protocol, md)
+}
+
+func newRPC(protocol string, md protoreflect.MethodDescriptor) *RPC {
+	rpc := &RPC{
+		Protocol:   protocol,
+		FullName:   string(md.FullName()),
+		Service:    string(md.Parent().FullName()),
+		Method:     string(md.Name()),
+		InputType:  string(md.Input().FullName()),
+		OutputType: string(md.Output().FullName()),
+		File:       md.ParentFile().Path(),
+
+		requestType:  string(md.Input().FullName()),
+		responseType: string(md.Output().FullName()),
+	}
+	if loc := md.ParentFile().SourceLocations().ByDescriptor(md); loc.Path != nil {
+		rpc.Line = loc.StartLine + 1
+		rpc.description = strings.Join(strings.Fields(loc.LeadingComments), " ")
+	}
+	return rpc
+}
+
+// resolveRPC returns the RPC which the request is mapped to by the first adapter which resolves it.
+// Link to the method definition is set if Document.ProtoSourceURL is given.
+func (d *Document) resolveRPC(r *http.Request, adapters []RPCAdapter) *RPC {
+	files := d.ProtoFiles
+	if files == nil {
+		files = protoregistry.GlobalFiles
+	}
+	for _, adapter := range adapters {
+		rpc := adapter(r, files)
+		if rpc == nil {
+			continue
+		}
+		if d.ProtoSourceURL != "" {
+			line := rpc.Line
+			if line == 0 {
+				line = 1
+			}
+			rpc.SourceURL = strings.NewReplacer("{file}", rpc.File, "{line}", strconv.Itoa(line)).Replace(d.ProtoSourceURL)
+		}
+		return rpc
+	}
+	return nil
+}
+
+// protoBufferOption returns the option with message types of the RPC. Types in the given option are
+// preferred.
+func (rpc *RPC) protoBufferOption(opt *ProtoBufferOption) *ProtoBufferOption {
+	o := ProtoBufferOption{}
+	if opt != nil {
+		o = *opt
+	}
+	if o.RequestUnmarshaler == nil && o.RequestMessage == nil && o.RequestType == "" {
+		o.RequestType = rpc.requestType
+	}
+	if o.ResponseUnmarshaler == nil && o.ResponseMessage == nil && o.ResponseType == "" {
+		o.ResponseType = rpc.responseType
+	}
+	return &o
+}
+
+// bodyType returns full name of the message mapped to the body. field is `*` for the whole message
+// or field name for the message field. If field is empty, def is used. Empty is returned if nothing
+// is mapped to the body.
+func bodyType(md protoreflect.MessageDescriptor, field, def string) string {
+	if field == "" {
+		field = def
+	}
+	switch field {
+	case "":
+		return ""
+	case "*":
+		return string(md.FullName())
+	}
+	fd := md.Fields().ByName(protoreflect.Name(field))
+	if fd == nil || fd.Message() == nil || fd.IsList() || fd.IsMap() {
+		return ""
+	}
+	return string(fd.Message().FullName())
+}
+
+// httpRuleField is the field number of `google.api.http` option in MethodOptions.
+const httpRuleField = 72295728
+
+// httpRule is HTTP binding of the method in `google.api.http` option.
+type httpRule struct {
+	method       string
+	path         string
+	body         string
+	responseBody string
+}
+
+// httpRules returns HTTP bindings of the method including additional bindings. The option is read
+// from encoded options, so google.api annotations don't need to be linked.
+func httpRules(md protoreflect.MethodDescriptor) []httpRule {
+	opts := md.Options()
+	if opts == nil {
+		return nil
+	}
+	b, err := protov2.Marshal(opts)
+	if err != nil {
+		return nil
+	}
+
+	var rules []httpRule
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return rules
+		}
+		b = b[n:]
+		if num == httpRuleField && typ == protowire.BytesType {
+			v, m := protowire.ConsumeBytes(b)
+			if m < 0 {
+				return rules
+			}
+			rules = append(rules, parseHTTPRule(v)...)
+		}
+		m := protowire.ConsumeFieldValue(num, typ, b)
+		if m < 0 {
+			return rules
+		}
+		b = b[m:]
+	}
+	return rules
+}
+
+// parseHTTPRule parses encoded google.api.HttpRule and its additional bindings.
+func parseHTTPRule(b []byte) []httpRule {
+	var (
+		rule       httpRule
+		additional []httpRule
+	)
+	methods := map[protowire.Number]string{2: "GET", 3: "PUT", 4: "POST", 5: "DELETE", 6: "PATCH"}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			break
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			m := protowire.ConsumeFieldValue(num, typ, b)
+			if m < 0 {
+				break
+			}
+			b = b[m:]
+			continue
+		}
+
+		v, m := protowire.ConsumeBytes(b)
+		if m < 0 {
+			break
+		}
+		b = b[m:]
+		switch num {
+		case 2, 3, 4, 5, 6:
+			rule.method, rule.path = methods[num], string(v)
+		case 7:
+			rule.body = string(v)
+		case 8:
+			rule.method, rule.path = parseCustomHTTPPattern(v)
+		case 11:
+			additional = append(additional, parseHTTPRule(v)...)
+		case 12:
+			rule.responseBody = string(v)
+		}
+	}
+
+	if rule.path == "" {
+		return additional
+	}
+	return append([]httpRule{rule}, additional...)
+}
+
+// parseCustomHTTPPattern parses encoded google.api.CustomHttpPattern and returns its kind and path.
+func parseCustomHTTPPattern(b []byte) (string, string) {
+	var kind, path string
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 || typ != protowire.BytesType {
+			break
+		}
+		v, m := protowire.ConsumeBytes(b[n:])
+		if m < 0 {
+			break
+		}
+		b = b[n+m:]
+		switch num {
+		case 1:
+			kind = string(v)
+		case 2:
+			path = string(v)
+		}
+	}
+	return kind, path
+}
+
+// matchPathTemplate reports whether the path matches grpc-gateway path template, e.g.,
+// `/v1/{name=users/*}/books/{book_id}:publish`. `*` matches one segment and `**` matches
+// the rest of segments.
+func matchPathTemplate(template, path string) bool {
+	var verb string
+	template, verb = splitVerb(template)
+	if verb != "" {
+		if !strings.HasSuffix(path, ":"+verb) {
+			return false
+		}
+		path = strings.TrimSuffix(path, ":"+verb)
+	}
+
+	patterns := templateSegments(template)
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, p := range patterns {
+		if p == "**" {
+			return len(segments) >= len(patterns)-i-1
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if p != "*" && p != segments[0] {
+			return false
+		}
+		segments = segments[1:]
+	}
+	return len(segments) == 0
+}
+
+// splitVerb splits the verb (e.g., `:publish`) of the template. Colons in variables are not verb.
+func splitVerb(template string) (string, string) {
+	i := strings.LastIndex(template, ":")
+	if i < 0 || strings.ContainsAny(template[i:], "/}") {
+		return template, ""
+	}
+	return template[:i], template[i+1:]
+}
+
+// templateSegments returns segment patterns of the template. Variables are replaced with their
+// patterns, e.g., `{name=users/*}` is `users/*` and `{id}` is `*`.
+func templateSegments(template string) []string {
+	var buf bytes.Buffer
+	for i := 0; i < len(template); i++ {
+		if template[i] != '{' {
+			buf.WriteByte(template[i])
+			continue
+		}
+		end := strings.IndexByte(template[i:], '}')
+		if end < 0 {
+			end = len(template) - i
+		}
+		v := template[i+1 : i+end]
+		if eq := strings.IndexByte(v, '='); eq >= 0 {
+			buf.WriteString(v[eq+1:])
+		} else {
+			buf.WriteByte('*')
+		}
+		i += end
+	}
+	return strings.Split(strings.TrimPrefix(buf.String(), "/"), "/")
+}
@@ -0,0 +1,341 @@
+package httpdoc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protowire"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// serviceFiles returns descriptors of `httpdoc.UserService` whose GetUser method is bound to
+// `GET /v1/users/{id}` and `POST /v1/users:lookup`.
+func serviceFiles(t *testing.T) *protoregistry.Files {
+	message := protodesc.ToFileDescriptorProto(proto.MessageV2(&UserProtoRequest{}).ProtoReflect().Descriptor().ParentFile())
+
+	var additional []byte
+	additional = protowire.AppendTag(additional, 4, protowire.BytesType)
+	additional = protowire.AppendString(additional, "/v1/users:lookup")
+	additional = protowire.AppendTag(additional, 7, protowire.BytesType)
+	additional = protowire.AppendString(additional, "*")
+
+	var rule []byte
+	rule = protowire.AppendTag(rule, 2, protowire.BytesType)
+	rule = protowire.AppendString(rule, "/v1/users/{id}")
+	rule = protowire.AppendTag(rule, 11, protowire.BytesType)
+	rule = protowire.AppendBytes(rule, additional)
+
+	var option []byte
+	option = protowire.AppendTag(option, httpRuleField, protowire.BytesType)
+	option = protowire.AppendBytes(option, rule)
+
+	opts := &descriptorpb.MethodOptions{}
+	opts.ProtoReflect().SetUnknown(option)
+
+	service := &descriptorpb.FileDescriptorProto{
+		Name:       protov2.String("service.proto"),
+		Package:    protov2.String("httpdoc"),
+		Syntax:     protov2.String("proto3"),
+		Dependency: []string{message.GetName()},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: protov2.String("UserService"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       protov2.String("GetUser"),
+				InputType:  protov2.String(".httpdoc.UserProtoRequest"),
+				OutputType: protov2.String(".httpdoc.UserProtoResponse"),
+				Options:    opts,
+			}},
+		}},
+		SourceCodeInfo: &descriptorpb.SourceCodeInfo{
+			Location: []*descriptorpb.SourceCodeInfo_Location{{
+				// service = 6, UserService = 0, method = 2, GetUser = 0
+				Path:            []int32{6, 0, 2, 0},
+				Span:            []int32{11, 2, 40},
+				LeadingComments: protov2.String(" Get the user.\n"),
+			}},
+		},
+	}
+
+	files, err := protodesc.NewFiles(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{message, service},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return files
+}
+
+func TestMatchPathTemplate(t *testing.T) {
+	cases := []struct {
+		template string
+		path     string
+		want     bool
+	}{
+		{"/v1/users", "/v1/users", true},
+		{"/v1/users", "/v1/users/1", false},
+		{"/v1/users/{id}", "/v1/users/1", true},
+		{"/v1/users/{id}", "/v1/users", false},
+		{"/v1/{name=users/*}", "/v1/users/1", true},
+		{"/v1/{name=users/*}", "/v1/books/1", false},
+		{"/v1/{name=users/*}/books/{book_id}", "/v1/users/1/books/2", true},
+		{"/v1/{name=files/**}", "/v1/files/a/b/c", true},
+		{"/v1/users/{id}:publish", "/v1/users/1:publish", true},
+		{"/v1/users/{id}:publish", "/v1/users/1", false},
+		{"/v1/users:lookup", "/v1/users:lookup", true},
+		{"/", "/", true},
+	}
+	for _, tc := range cases {
+		if got := matchPathTemplate(tc.template, tc.path); got != tc.want {
+			t.Fatalf("matchPathTemplate(%q, %q): got %v, want %v", tc.template, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestHTTPRules(t *testing.T) {
+	files := serviceFiles(t)
+	resolver := GRPCGatewayAdapter()
+
+	cases := []struct {
+		method       string
+		path         string
+		requestType  string
+		responseType string
+	}{
+		{"GET", "/v1/users/1", "", "httpdoc.UserProtoResponse"},
+		{"POST", "/v1/users:lookup", "httpdoc.UserProtoRequest", "httpdoc.UserProtoResponse"},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest(tc.method, tc.path, nil)
+		rpc := resolver(r, files)
+		if rpc == nil {
+			t.Fatalf("%s %s: expect to be resolved", tc.method, tc.path)
+		}
+		if rpc.FullName != "httpdoc.UserService.GetUser" {
+			t.Fatalf("got %q, want %q", rpc.FullName, "httpdoc.UserService.GetUser")
+		}
+		if rpc.requestType != tc.requestType || rpc.responseType != tc.responseType {
+			t.Fatalf("%s %s: got %q and %q, want %q and %q", tc.method, tc.path, rpc.requestType, rpc.responseType, tc.requestType, tc.responseType)
+		}
+	}
+
+	if rpc := resolver(httptest.NewRequest("DELETE", "/v1/users/1", nil), files); rpc != nil {
+		t.Fatalf("expect not to be resolved: %#v", rpc)
+	}
+}
+
+func TestRecord_RPC_GRPCGateway(t *testing.T) {
+	doc := &Document{
+		ProtoFiles:     serviceFiles(t),
+		ProtoSourceURL: "https://example.com/proto/{file}#L{line}",
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 1, "name": "tcnksm"}`))
+	})
+
+	ts := httptest.NewServer(Record(handler, doc, &RecordOption{
+		RPCAdapters: []RPCAdapter{TwirpAdapter(""), GRPCGatewayAdapter()},
+	}))
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/v1/users/1")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	entry := doc.Entries[0]
+	want := &RPC{
+		Protocol:   ProtocolGRPCGateway,
+		FullName:   "httpdoc.UserService.GetUser",
+		Service:    "httpdoc.UserService",
+		Method:     "GetUser",
+		InputType:  "httpdoc.UserProtoRequest",
+		OutputType: "httpdoc.UserProtoResponse",
+		File:       "service.proto",
+		Line:       12,
+		SourceURL:  "https://example.com/proto/service.proto#L12",
+
+		responseType: "httpdoc.UserProtoResponse",
+		description:  "Get the user.",
+	}
+	if !reflect.DeepEqual(entry.RPC, want) {
+		t.Fatalf("got %#v, want %#v", entry.RPC, want)
+	}
+	if entry.Description != "Get the user." {
+		t.Fatalf("got %q, want %q", entry.Description, "Get the user.")
+	}
+
+	var names []string
+	for _, f := range entry.ResponseFields {
+		names = append(names, f.Name+"|"+f.Type)
+	}
+	if want := []string{"Active|boolean", "Id|integer", "Name|string", "Setting|object", "Setting.Email|string"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("got %q, want %q", names, want)
+	}
+
+	var buf bytes.Buffer
+	if err := doc.generate(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if want := "RPC: [`httpdoc.UserService.GetUser`](https://example.com/proto/service.proto#L12)(`httpdoc.UserProtoRequest`) returns (`httpdoc.UserProtoResponse`) via grpc-gateway"; !strings.Contains(buf.String(), want) {
+		t.Fatalf("expect %q to contain %q", buf.String(), want)
+	}
+}
+
+// getOptions returns method options which bind the method to `GET <path>`.
+func getOptions(path string) *descriptorpb.MethodOptions {
+	var rule []byte
+	rule = protowire.AppendTag(rule, 2, protowire.BytesType)
+	rule = protowire.AppendString(rule, path)
+
+	var option []byte
+	option = protowire.AppendTag(option, httpRuleField, protowire.BytesType)
+	option = protowire.AppendBytes(option, rule)
+
+	opts := &descriptorpb.MethodOptions{}
+	opts.ProtoReflect().SetUnknown(option)
+	return opts
+}
+
+func TestGRPCGatewayAdapter_Overlapping(t *testing.T) {
+	message := protodesc.ToFileDescriptorProto(proto.MessageV2(&UserProtoRequest{}).ProtoReflect().Descriptor().ParentFile())
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{message}}
+	for _, service := range []struct {
+		file, name string
+		methods    map[string]string
+	}{
+		{"a.proto", "AService", map[string]string{"GetUser": "/v1/users/{id}", "GetMe": "/v1/users/me"}},
+		{"b.proto", "BService", map[string]string{"GetUser": "/v1/users/{id}"}},
+	} {
+		sd := &descriptorpb.ServiceDescriptorProto{Name: protov2.String(service.name)}
+		for name, path := range service.methods {
+			sd.Method = append(sd.Method, &descriptorpb.MethodDescriptorProto{
+				Name:       protov2.String(name),
+				InputType:  protov2.String(".httpdoc.UserProtoRequest"),
+				OutputType: protov2.String(".httpdoc.UserProtoResponse"),
+				Options:    getOptions(path),
+			})
+		}
+		set.File = append(set.File, &descriptorpb.FileDescriptorProto{
+			Name:       protov2.String(service.file),
+			Package:    protov2.String("httpdoc"),
+			Syntax:     protov2.String("proto3"),
+			Dependency: []string{message.GetName()},
+			Service:    []*descriptorpb.ServiceDescriptorProto{sd},
+		})
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	adapter := GRPCGatewayAdapter()
+	cases := map[string]string{
+		"/v1/users/me": "httpdoc.AService.GetMe",
+		"/v1/users/1":  "httpdoc.AService.GetUser",
+	}
+	// Files are iterated in random order, so resolve many times to check it's deterministic.
+	for i := 0; i < 20; i++ {
+		for path, want := range cases {
+			rpc := adapter(httptest.NewRequest("GET", path, nil), files)
+			if rpc == nil || rpc.FullName != want {
+				t.Fatalf("%s: got %#v, want %s", path, rpc, want)
+			}
+		}
+	}
+}
+
+func TestLiteralSegments(t *testing.T) {
+	cases := map[string]int{
+		"/v1/users/{id}":         2,
+		"/v1/users/me":           3,
+		"/v1/{name=users/*}":     2,
+		"/v1/users:lookup":       3,
+		"/v1/{name=projects/**}": 2,
+	}
+	for template, want := range cases {
+		if got := literalSegments(template); got != want {
+			t.Fatalf("%s: got %d, want %d", template, got, want)
+		}
+	}
+}
+
+func TestRecord_RPC_ConnectTwirp(t *testing.T) {
+	cases := []struct {
+		adapter     RPCAdapter
+		path        string
+		contentType string
+		protocol    string
+	}{
+		{ConnectAdapter(), "/httpdoc.UserService/GetUser", "application/proto", ProtocolConnect},
+		{TwirpAdapter(""), "/twirp/httpdoc.UserService/GetUser", "application/protobuf", ProtocolTwirp},
+		{TwirpAdapter("/api/"), "/api/httpdoc.UserService/GetUser", "application/protobuf", ProtocolTwirp},
+	}
+	for _, tc := range cases {
+		doc := &Document{ProtoFiles: serviceFiles(t)}
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ioutil.ReadAll(r.Body)
+			buf, _ := proto.Marshal(&UserProtoResponse{Id: 1, Name: "tcnksm"})
+			w.Header().Set("Content-Type", tc.contentType)
+			w.Write(buf)
+		})
+
+		ts := httptest.NewServer(Record(handler, doc, &RecordOption{
+			RPCAdapters: []RPCAdapter{tc.adapter},
+		}))
+
+		body, _ := proto.Marshal(&UserProtoRequest{Id: 1})
+		res, err := http.Post(ts.URL+tc.path, tc.contentType, bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		res.Body.Close()
+		ts.Close()
+
+		entry := doc.Entries[0]
+		if entry.RPC == nil || entry.RPC.Protocol != tc.protocol {
+			t.Fatalf("%s: expect to be mapped to RPC by %s: %#v", tc.path, tc.protocol, entry.RPC)
+		}
+		if want := `"id": 1`; !strings.Contains(entry.RequestExample, want) {
+			t.Fatalf("expect %q to contain %q", entry.RequestExample, want)
+		}
+		if want := `"name": "tcnksm"`; !strings.Contains(entry.ResponseExample, want) {
+			t.Fatalf("expect %q to contain %q", entry.ResponseExample, want)
+		}
+	}
+}
+
+func TestRecord_RPC_NotFound(t *testing.T) {
+	doc := &Document{ProtoFiles: serviceFiles(t)}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+
+	ts := httptest.NewServer(Record(handler, doc, &RecordOption{
+		RPCAdapters: []RPCAdapter{ConnectAdapter(), TwirpAdapter("")},
+	}))
+	defer ts.Close()
+
+	for _, path := range []string{"/httpdoc.UserService/Unknown", "/httpdoc.Unknown/GetUser", "/v1/users"} {
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(`{}`))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		res.Body.Close()
+	}
+	for _, e := range doc.Entries {
+		if e.RPC != nil {
+			t.Fatalf("%s: expect not to be mapped to RPC: %#v", e.Path, e.RPC)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package httpdoc
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema describes type and restrictions of a request or response field. It's populated from the
+// struct which the body is unmarshaled to. To describe a field explicitly, set TestCase.Schema.
+type Schema struct {
+	// Type is JSON type of the field, e.g., `string`, `integer`, `number`, `boolean`, `object`,
+	// `array of string` or `enum`.
+	Type string
+
+	// Required reports whether the field is always present. Fields are required unless they are
+	// pointers or have `omitempty` in json tag. `validate:"required"` tag or `req` in protobuf tag
+	// makes fields required.
+	Required bool
+
+	// Format is the format of the type, e.g., `int64`, `date-time` or enum name. It can be set by
+	// `format` tag.
+	Format string
+
+	// Example is an example value. It can be set by `example` tag.
+	Example string
+
+	// Constraints are restrictions of the value, e.g., `nullable` for pointers. Options in
+	// `validate` tag (e.g., `min=1`) are also included.
+	Constraints []string
+}
+
+// merge overrides the schema by non-empty fields of the explicit schema. Since Required can't be
+// told from unset, the explicit schema can only make the field required.
+func (s Schema) merge(explicit *Schema) Schema {
+	if explicit == nil {
+		return s
+	}
+	if explicit.Type != "" {
+		s.Type = explicit.Type
+	}
+	if explicit.Format != "" {
+		s.Format = explicit.Format
+	}
+	if explicit.Example != "" {
+		s.Example = explicit.Example
+	}
+	if explicit.Constraints != nil {
+		s.Constraints = explicit.Constraints
+	}
+	if explicit.Required {
+		s.Required = true
+	}
+	return s
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schema returns the schema of the field at the path in v. If the type of the field is an interface,
+// the type of actual value is used instead.
+func (p fieldPath) schema(v interface{}, actual interface{}) Schema {
+	var (
+		s     Schema
+		field *reflect.StructField
+	)
+
+	typ := reflect.TypeOf(v)
+	if typ == nil {
+		return s
+	}
+walk:
+	for _, e := range p {
+		for typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		field = nil
+		switch {
+		case typ.Kind() == reflect.Interface:
+			// The type is known only from actual value, e.g., map[string]interface{}.
+			break walk
+		case e.kind == pathField && typ.Kind() == reflect.Struct:
+			f, ok := typ.FieldByName(e.name)
+			if !ok {
+				return s
+			}
+			field = &f
+			typ = f.Type
+		case typ.Kind() == reflect.Map:
+			typ = typ.Elem()
+		case e.kind == pathIndex || e.kind == pathWildcard:
+			if typ.Kind() != reflect.Slice && typ.Kind() != reflect.Array {
+				return s
+			}
+			typ = typ.Elem()
+		default:
+			return s
+		}
+	}
+
+	if typ.Kind() == reflect.Interface && actual != nil {
+		typ = reflect.TypeOf(actual)
+	}
+	s.Type, s.Format = typeName(typ)
+	s.Required = true
+	if typ.Kind() == reflect.Ptr {
+		s.Required = false
+		s.Constraints = append(s.Constraints, "nullable")
+	}
+	if field != nil {
+		s.tag(field.Tag)
+	}
+	return s
+}
+
+// tag populates the schema from the struct tag.
+func (s *Schema) tag(tag reflect.StructTag) {
+	if json := strings.Split(tag.Get("json"), ","); len(json) > 1 {
+		for _, opt := range json[1:] {
+			if opt == "omitempty" {
+				s.Required = false
+			}
+		}
+	}
+
+	if pb := tag.Get("protobuf"); pb != "" {
+		for _, opt := range strings.Split(pb, ",") {
+			switch {
+			case opt == "req":
+				s.Required = true
+			case opt == "opt" || opt == "rep":
+				s.Required = false
+			case strings.HasPrefix(opt, "enum="):
+				s.Type, s.Format = "enum", strings.TrimPrefix(opt, "enum=")
+			}
+		}
+	}
+
+	if v := tag.Get("validate"); v != "" {
+		for _, opt := range strings.Split(v, ",") {
+			switch opt {
+			case "required":
+				s.Required = true
+			case "omitempty", "":
+			default:
+				s.Constraints = append(s.Constraints, opt)
+			}
+		}
+	}
+
+	if v := tag.Get("format"); v != "" {
+		s.Format = v
+	}
+	if v := tag.Get("example"); v != "" {
+		s.Example = v
+	}
+}
+
+// typeName returns JSON type name and the format of the Go type.
+func typeName(typ reflect.Type) (string, string) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == timeType {
+		return "string", "date-time"
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return "string", ""
+	case reflect.Bool:
+		return "boolean", ""
+	case reflect.Int32, reflect.Uint32:
+		return "integer", "int32"
+	case reflect.Int64, reflect.Uint64:
+		return "integer", "int64"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return "integer", ""
+	case reflect.Float32:
+		return "number", "float"
+	case reflect.Float64:
+		return "number", "double"
+	case reflect.Struct, reflect.Map:
+		return "object", ""
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return "string", "byte"
+		}
+		if elem, _ := typeName(typ.Elem()); elem != "" {
+			return "array of " + elem, ""
+		}
+		return "array", ""
+	}
+	return "", ""
+}
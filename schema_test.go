@@ -0,0 +1,117 @@
+package httpdoc
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Account struct {
+	ID        string                 `json:"id" format:"uuid" example:"f47ac10b-58cc-4372-a567-0e02b2c3d479"`
+	Age       int                    `json:"age,omitempty" validate:"required,min=0,max=150"`
+	Nickname  *string                `json:"nickname"`
+	CreatedAt time.Time              `json:"created_at"`
+	Avatar    []byte                 `json:"avatar"`
+	Scores    []float64              `json:"scores"`
+	Extra     map[string]interface{} `json:"extra"`
+}
+
+func TestFieldPath_Schema(t *testing.T) {
+	nickname := "deeeet"
+	account := &Account{
+		Nickname: &nickname,
+		Scores:   []float64{1.5},
+		Extra:    map[string]interface{}{"note": "hello"},
+	}
+
+	cases := []struct {
+		target string
+		want   Schema
+	}{
+		{"ID", Schema{Type: "string", Required: true, Format: "uuid", Example: "f47ac10b-58cc-4372-a567-0e02b2c3d479"}},
+		{"Age", Schema{Type: "integer", Required: true, Constraints: []string{"min=0", "max=150"}}},
+		{"Nickname", Schema{Type: "string", Constraints: []string{"nullable"}}},
+		{"CreatedAt", Schema{Type: "string", Required: true, Format: "date-time"}},
+		{"Avatar", Schema{Type: "string", Required: true, Format: "byte"}},
+		{"Scores", Schema{Type: "array of number", Required: true}},
+		{"Scores[*]", Schema{Type: "number", Required: true, Format: "double"}},
+		{`Extra["note"]`, Schema{Type: "string", Required: true}},
+		{"Unknown", Schema{}},
+	}
+
+	for _, tc := range cases {
+		path, err := parseFieldPath(tc.target)
+		if err != nil {
+			t.Fatalf("%s: err: %s", tc.target, err)
+		}
+		values := path.values(account)
+		if got := path.schema(account, values[0]); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: got %#v, want %#v", tc.target, got, tc.want)
+		}
+	}
+}
+
+func TestFieldPath_Schema_Proto(t *testing.T) {
+	path, _ := parseFieldPath("Id")
+	want := Schema{Type: "integer", Format: "int32"}
+	if got := path.schema(&UserProtoResponse{}, int32(0)); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestValidateFields_Schema(t *testing.T) {
+	var fields []Data
+	validator := newValidator()
+	validator.validateFields(t, []TestCase{
+		NewTestCase("ID", "", ""),
+		{Target: "Age", Expected: 0, Schema: &Schema{Example: "20"}},
+		{Target: "Nickname", Expected: (*string)(nil), Schema: &Schema{Required: true}},
+	}, &Account{}, &fields)
+
+	want := []Schema{
+		{Type: "string", Required: true, Format: "uuid", Example: "f47ac10b-58cc-4372-a567-0e02b2c3d479"},
+		{Type: "integer", Required: true, Example: "20", Constraints: []string{"min=0", "max=150"}},
+		{Type: "string", Required: true, Constraints: []string{"nullable"}},
+	}
+	for i, f := range fields {
+		if !reflect.DeepEqual(f.Schema, want[i]) {
+			t.Fatalf("%s: got %#v, want %#v", f.Name, f.Schema, want[i])
+		}
+	}
+}
+
+func TestDocument_Generate_Schema(t *testing.T) {
+	doc := &Document{
+		Entries: []Entry{
+			{
+				Method:             "GET",
+				Path:               "/v1/account",
+				ResponseStatusCode: 200,
+				ResponseFields: []Data{
+					{Name: "Age", Value: 20, Description: "Account age", Schema: Schema{Type: "integer", Required: true, Constraints: []string{"min=0", "max=150"}}},
+					{Name: "CreatedAt", Value: IsRFC3339(), Schema: Schema{Type: "string", Format: "date-time"}},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := doc.generate(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, want := range []string{
+		"| Name  | Type  | Required | Value  | Description | Constraints |\n",
+		"| Age | integer | yes | 20 | Account age | min=0, max=150 |\n",
+		"| CreatedAt | string (date-time) |  | RFC 3339 date-time |  |  |\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expect %q to contain %q", buf.String(), want)
+		}
+	}
+	if strings.Contains(buf.String(), "Example |") {
+		t.Fatal("expect example column not to be written")
+	}
+}
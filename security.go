@@ -0,0 +1,271 @@
+package httpdoc
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Types of SecurityScheme.
+const (
+	// SecurityBearer is bearer token sent in `Authorization` header.
+	SecurityBearer = "bearer"
+
+	// SecurityBasic is basic authentication.
+	SecurityBasic = "basic"
+
+	// SecurityAPIKey is API key sent in header, query parameter or cookie. SecurityScheme.In and
+	// SecurityScheme.ParamName must be provided.
+	SecurityAPIKey = "apiKey"
+
+	// SecurityOAuth2 is OAuth2 access token sent as bearer token in `Authorization` header.
+	SecurityOAuth2 = "oauth2"
+)
+
+// Locations of API key.
+const (
+	InHeader = "header"
+	InQuery  = "query"
+	InCookie = "cookie"
+)
+
+// SecurityScheme describes how endpoints authenticate. This is almost same as OpenAPI security scheme.
+type SecurityScheme struct {
+	// Name is identifier of the scheme (e.g., `bearerAuth`). It's referred by SecurityRequirement.Scheme.
+	Name string
+
+	// Type is type of the scheme. SecurityBearer, SecurityBasic, SecurityAPIKey or SecurityOAuth2.
+	Type string
+
+	// In is location of API key. InHeader, InQuery or InCookie. This is used only for SecurityAPIKey.
+	In string
+
+	// ParamName is header, query parameter or cookie name of API key (e.g., `token`).
+	// This is used only for SecurityAPIKey.
+	ParamName string
+
+	// Description is description of the scheme, e.g., how to get the credential.
+	Description string
+
+	// Scopes is available OAuth2 scopes and their descriptions. This is used only for SecurityOAuth2.
+	Scopes map[string]string
+}
+
+// SecurityRequirement is a security scheme which endpoint requires.
+type SecurityRequirement struct {
+	// Scheme is SecurityScheme.Name.
+	Scheme string
+
+	// Scopes is OAuth2 scopes which endpoint requires.
+	Scopes []string
+}
+
+// Location returns human readable description of where the credential is sent. This is used for templating.
+func (s SecurityScheme) Location() string {
+	switch s.Type {
+	case SecurityBearer, SecurityOAuth2:
+		return "`Authorization: Bearer {token}` header"
+	case SecurityBasic:
+		return "`Authorization: Basic {credentials}` header"
+	case SecurityAPIKey:
+		switch s.In {
+		case InQuery:
+			return fmt.Sprintf("`%s` query parameter", s.ParamName)
+		case InCookie:
+			return fmt.Sprintf("`%s` cookie", s.ParamName)
+		default:
+			return fmt.Sprintf("`%s` header", s.ParamName)
+		}
+	}
+	return ""
+}
+
+// SortedScopes returns OAuth2 scopes sorted by name. This is used for templating.
+func (s SecurityScheme) SortedScopes() []Data {
+	names := make([]string, 0, len(s.Scopes))
+	for name := range s.Scopes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	scopes := make([]Data, 0, len(names))
+	for _, name := range names {
+		scopes = append(scopes, Data{Name: name, Description: s.Scopes[name]})
+	}
+	return scopes
+}
+
+// securityScheme returns the scheme which has the given name. Bearer token and basic authentication
+// which are detected without declaration are named by their types.
+func (d *Document) securityScheme(name string) (SecurityScheme, bool) {
+	for _, s := range d.SecuritySchemes {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	if name == SecurityBearer || name == SecurityBasic {
+		return SecurityScheme{Name: name, Type: name}, true
+	}
+	return SecurityScheme{}, false
+}
+
+// AuthSchemes returns Document.SecuritySchemes and schemes which are detected from recorded entries
+// without declaration.
+func (v *documentView) AuthSchemes() []SecurityScheme {
+	schemes := append([]SecurityScheme(nil), v.SecuritySchemes...)
+	declared := make(map[string]bool)
+	for _, s := range schemes {
+		declared[s.Name] = true
+	}
+	for _, e := range v.Document.Entries {
+		for _, req := range e.Security {
+			if declared[req.Scheme] {
+				continue
+			}
+			if s, ok := v.securityScheme(req.Scheme); ok {
+				declared[req.Scheme] = true
+				schemes = append(schemes, s)
+			}
+		}
+	}
+	return schemes
+}
+
+// detectSecurity detects security requirements from the request. API keys are detected only if they
+// are declared in Document.SecuritySchemes. Bearer token and basic authentication are detected from
+// `Authorization` header. If the scheme is not declared, the requirement refers the scheme whose name
+// is the type (e.g., `bearer`). Such schemes are documented by documentView.AuthSchemes.
+func (d *Document) detectSecurity(r *http.Request) []SecurityRequirement {
+	var requirements []SecurityRequirement
+	for _, s := range d.SecuritySchemes {
+		if s.Type != SecurityAPIKey {
+			continue
+		}
+		var found bool
+		switch s.In {
+		case InQuery:
+			_, found = r.URL.Query()[s.ParamName]
+		case InCookie:
+			_, err := r.Cookie(s.ParamName)
+			found = err == nil
+		default:
+			found = r.Header.Get(s.ParamName) != ""
+		}
+		if found {
+			requirements = append(requirements, SecurityRequirement{Scheme: s.Name})
+		}
+	}
+
+	auth := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+	var types []string
+	switch strings.ToLower(auth[0]) {
+	case "bearer":
+		types = []string{SecurityBearer, SecurityOAuth2}
+	case "basic":
+		types = []string{SecurityBasic}
+	default:
+		return requirements
+	}
+
+	for _, s := range d.SecuritySchemes {
+		for _, typ := range types {
+			if s.Type == typ {
+				return append(requirements, SecurityRequirement{Scheme: s.Name})
+			}
+		}
+	}
+
+	return append(requirements, SecurityRequirement{Scheme: types[0]})
+}
+
+// redactSecurity replaces credential values of the required schemes in documented request headers
+// and parameters with placeholders, e.g., `Bearer {token}`. Values of other secret headers and
+// parameters (see Document.Secrets) are also replaced even if endpoint doesn't require authentication,
+// e.g., `Token {credentials}` or `{x_auth_token}`.
+func (d *Document) redactSecurity(requirements []SecurityRequirement, headers, params []Data) {
+	redacted := make(map[string]bool)
+	redact := func(data []Data, name string, f func(v string) string) {
+		for i := range data {
+			if strings.EqualFold(data[i].Name, name) {
+				data[i].Value = f(fmt.Sprint(data[i].Value))
+				redacted[strings.ToLower(name)] = true
+			}
+		}
+	}
+
+	for _, req := range requirements {
+		s, ok := d.securityScheme(req.Scheme)
+		if !ok {
+			continue
+		}
+
+		switch s.Type {
+		case SecurityBearer, SecurityOAuth2:
+			redact(headers, "Authorization", func(string) string { return "Bearer {token}" })
+		case SecurityBasic:
+			redact(headers, "Authorization", func(string) string { return "Basic {credentials}" })
+		case SecurityAPIKey:
+			placeholder := "{" + s.ParamName + "}"
+			switch s.In {
+			case InQuery:
+				redact(params, s.ParamName, func(string) string { return placeholder })
+			case InCookie:
+				redact(headers, "Cookie", func(v string) string { return redactCookie(v, s.ParamName, placeholder) })
+			default:
+				redact(headers, s.ParamName, func(string) string { return placeholder })
+			}
+		}
+	}
+
+	for _, data := range [][]Data{headers, params} {
+		for i := range data {
+			if !d.isSecret(data[i].Name) || redacted[strings.ToLower(data[i].Name)] {
+				continue
+			}
+			data[i].Value = redactSecret(data[i].Name, fmt.Sprint(data[i].Value))
+		}
+	}
+}
+
+// redactSecret returns the placeholder of the secret value. The scheme of `Authorization` header
+// is kept, e.g., `Token {credentials}`.
+func redactSecret(name, value string) string {
+	if strings.EqualFold(name, "Authorization") || strings.EqualFold(name, "Proxy-Authorization") {
+		auth := strings.SplitN(value, " ", 2)
+		switch {
+		case len(auth) < 2:
+			return "{credentials}"
+		case strings.EqualFold(auth[0], "bearer"):
+			return auth[0] + " {token}"
+		default:
+			return auth[0] + " {credentials}"
+		}
+	}
+	return "{" + variableName(name) + "}"
+}
+
+// redactCookie replaces the value of the cookie which has the given name in `Cookie` header.
+func redactCookie(header, name, placeholder string) string {
+	cookies := strings.Split(header, ";")
+	for i, c := range cookies {
+		kv := strings.SplitN(strings.TrimSpace(c), "=", 2)
+		if kv[0] == name {
+			cookies[i] = name + "=" + placeholder
+		} else {
+			cookies[i] = strings.TrimSpace(c)
+		}
+	}
+	return strings.Join(cookies, "; ")
+}
+
+// securityParamNames returns header and parameter names of declared API keys. They are treated as secret.
+func (d *Document) securityParamNames() []string {
+	var names []string
+	for _, s := range d.SecuritySchemes {
+		if s.Type == SecurityAPIKey && s.In != InCookie {
+			names = append(names, s.ParamName)
+		}
+	}
+	return names
+}
@@ -0,0 +1,170 @@
+package httpdoc
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecord_Security(t *testing.T) {
+	cases := []struct {
+		schemes      []SecurityScheme
+		option       *RecordOption
+		setup        func(r *http.Request)
+		wantSecurity []SecurityRequirement
+		wantSchemes  int
+		wantHeader   string
+		wantParam    string
+	}{
+		{
+			nil,
+			nil,
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") },
+			[]SecurityRequirement{{Scheme: "bearer"}},
+			1,
+			"Bearer {token}",
+			"",
+		},
+
+		{
+			[]SecurityScheme{{Name: "oauth", Type: SecurityOAuth2}},
+			&RecordOption{Security: []SecurityRequirement{{Scheme: "oauth", Scopes: []string{"read"}}}},
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") },
+			[]SecurityRequirement{{Scheme: "oauth", Scopes: []string{"read"}}},
+			1,
+			"Bearer {token}",
+			"",
+		},
+
+		{
+			[]SecurityScheme{{Name: "token", Type: SecurityAPIKey, In: InQuery, ParamName: "token"}},
+			nil,
+			func(r *http.Request) { r.URL.RawQuery = "token=secret" },
+			[]SecurityRequirement{{Scheme: "token"}},
+			1,
+			"",
+			"{token}",
+		},
+
+		// Secret values are redacted even if endpoint doesn't require authentication.
+		{
+			nil,
+			&RecordOption{Security: []SecurityRequirement{}},
+			func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") },
+			[]SecurityRequirement{},
+			0,
+			"Bearer {token}",
+			"",
+		},
+
+		{
+			nil,
+			nil,
+			func(r *http.Request) {
+				r.Header.Set("Authorization", "Token secret")
+				r.URL.RawQuery = "token=secret"
+			},
+			nil,
+			0,
+			"Token {credentials}",
+			"{token}",
+		},
+	}
+
+	for i, tc := range cases {
+		doc := &Document{SecuritySchemes: tc.schemes}
+		r := httptest.NewRequest("GET", "/v1/user", nil)
+		tc.setup(r)
+		Record(http.HandlerFunc(testHandler), doc, tc.option).ServeHTTP(httptest.NewRecorder(), r)
+
+		entry := doc.Entries[0]
+		if !reflect.DeepEqual(entry.Security, tc.wantSecurity) {
+			t.Fatalf("#%d: got %#v, want %#v", i, entry.Security, tc.wantSecurity)
+		}
+		if got := len(doc.SecuritySchemes); got != len(tc.schemes) {
+			t.Fatalf("#%d: expect declared schemes not to be modified: %#v", i, doc.SecuritySchemes)
+		}
+		if got := len(doc.view().AuthSchemes()); got != tc.wantSchemes {
+			t.Fatalf("#%d: got %d schemes, want %d", i, got, tc.wantSchemes)
+		}
+		for _, h := range entry.RequestHeaders {
+			if h.Name == "Authorization" && h.Value != tc.wantHeader {
+				t.Fatalf("#%d: got %q, want %q", i, h.Value, tc.wantHeader)
+			}
+		}
+		for _, p := range entry.RequestParams {
+			if p.Name == "token" && p.Value != tc.wantParam {
+				t.Fatalf("#%d: got %q, want %q", i, p.Value, tc.wantParam)
+			}
+		}
+	}
+}
+
+func TestRedactSecret(t *testing.T) {
+	cases := []struct {
+		name, value, want string
+	}{
+		{"Authorization", "Bearer secret", "Bearer {token}"},
+		{"Authorization", "Token secret", "Token {credentials}"},
+		{"Authorization", "secret", "{credentials}"},
+		{"X-Auth-Token", "secret", "{x_auth_token}"},
+		{"Cookie", "session=secret", "{cookie}"},
+	}
+	for _, tc := range cases {
+		if got := redactSecret(tc.name, tc.value); got != tc.want {
+			t.Fatalf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRedactCookie(t *testing.T) {
+	got := redactCookie("lang=ja;session=secret; theme=dark", "session", "{session}")
+	if want := "lang=ja; session={session}; theme=dark"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestDocument_IsSecret_SecuritySchemes(t *testing.T) {
+	doc := &Document{
+		SecuritySchemes: []SecurityScheme{{Name: "key", Type: SecurityAPIKey, In: InHeader, ParamName: "X-Client-Key"}},
+	}
+	if !doc.isSecret("x-client-key") {
+		t.Fatal("expect API key to be secret")
+	}
+}
+
+func TestDocument_Generate_Security(t *testing.T) {
+	doc := &Document{
+		SecuritySchemes: []SecurityScheme{
+			{Name: "session", Type: SecurityAPIKey, In: InCookie, ParamName: "sid", Description: "Session cookie"},
+			{Name: "oauth", Type: SecurityOAuth2, Scopes: map[string]string{"write": "Write user", "read": "Read user"}},
+		},
+		Entries: []Entry{
+			{
+				Method:             "GET",
+				Path:               "/v1/user",
+				ResponseStatusCode: 200,
+				Security:           []SecurityRequirement{{Scheme: "oauth", Scopes: []string{"read", "write"}}},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := doc.generate(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, want := range []string{
+		"| session | apiKey | `sid` cookie | Session cookie |",
+		"| oauth | oauth2 | `Authorization: Bearer {token}` header |  |",
+		"| read | Read user |\n| write | Write user |",
+		"Authentication: `oauth` (scopes: `read`, `write`)",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expect %q to contain %q", buf.String(), want)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package httpdoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// SnapshotUpdate is the value of EnvHTTPDoc to update snapshots by Validator.MatchSnapshot
+	// instead of comparing responses with them. Since it's not empty, documentation is also
+	// generated then.
+	SnapshotUpdate = "update"
+
+	// snapshotMask replaces values of volatile fields in snapshots.
+	snapshotMask = "{masked}"
+)
+
+// defaultSnapshotDir is the directory where snapshots are stored. It's relative to the package
+// directory where tests run.
+var defaultSnapshotDir = "testdata"
+
+// SnapshotOption is option for Validator.MatchSnapshot.
+type SnapshotOption struct {
+	// Headers is list of response headers written in the snapshot. By default, only `Content-Type`
+	// is written because other headers (e.g., `Date`) often change on every request.
+	Headers []string
+
+	// Masks is list of json paths of volatile fields like timestamps and IDs in the response body
+	// (e.g., `created_at` or `items[*].id`). The syntax is the same as TestCase.Target of json fields.
+	// Their values are replaced with `{masked}` in the snapshot.
+	Masks []string
+
+	// FailIfMissing option, the test fails if the snapshot doesn't exist instead of writing it.
+	// It's useful on CI not to create missing snapshots silently.
+	FailIfMissing bool
+}
+
+// MatchSnapshot compares the normalized response with the snapshot `testdata/<name>.snapshot`.
+// The response is normalized into the status, selected headers and the body. Json body is indented
+// with sorted keys and protocol buffer body is converted into json with ProtoBufferOption message
+// types. Name is a slash separated path relative to `testdata` and can't go out of it.
+//
+// If the snapshot doesn't exist (unless SnapshotOption.FailIfMissing is true) or EnvHTTPDoc is
+// SnapshotUpdate (`HTTPDOC=update`), the snapshot is written instead.
+//
+//   validator.MatchSnapshot(t, "get-user", &httpdoc.SnapshotOption{
+//       Masks: []string{"id", "created_at"},
+//   })
+//
+func (v *Validator) MatchSnapshot(t *testing.T, name string, opt *SnapshotOption) {
+	if opt == nil {
+		opt = &SnapshotOption{}
+	}
+
+	file, err := snapshotFile(name)
+	if err != nil {
+		tFatalf(t, "%s", err)
+		return
+	}
+
+	actual, err := v.snapshot(opt)
+	if err != nil {
+		tFatalf(t, "Failed to create snapshot %q: %s", name, err)
+		return
+	}
+
+	expected, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) && opt.FailIfMissing && os.Getenv(EnvHTTPDoc) != SnapshotUpdate {
+		tFatalf(t, "snapshot %q does not exist (run tests with %s=%s to create it)", name, EnvHTTPDoc, SnapshotUpdate)
+		return
+	}
+	if os.Getenv(EnvHTTPDoc) == SnapshotUpdate || os.IsNotExist(err) {
+		if err := writeSnapshot(file, actual); err != nil {
+			tFatalf(t, "Failed to write snapshot %q: %s", name, err)
+		}
+		return
+	}
+	if err != nil {
+		tFatalf(t, "Failed to read snapshot %q: %s", name, err)
+		return
+	}
+
+	if !bytes.Equal(expected, actual) {
+		tFatalf(t, "response does not match snapshot %q (run tests with %s=%s to update it):\n%s",
+			name, EnvHTTPDoc, SnapshotUpdate, diffLines(string(expected), string(actual)))
+	}
+}
+
+// snapshotFile returns the file path of the snapshot. Names which go out of the snapshot directory
+// (e.g., `../users`) are rejected.
+func snapshotFile(name string) (string, error) {
+	clean := path.Clean(filepath.ToSlash(name))
+	if name == "" || path.IsAbs(clean) || filepath.IsAbs(name) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid snapshot name %q", name)
+	}
+	return filepath.Join(defaultSnapshotDir, filepath.FromSlash(clean)+".snapshot"), nil
+}
+
+// snapshot returns the normalized response.
+func (v *Validator) snapshot(opt *SnapshotOption) ([]byte, error) {
+	var buf bytes.Buffer
+	code := v.record.responseStatusCode
+	if code == 0 {
+		code = http.StatusOK
+	}
+	fmt.Fprintf(&buf, "%d %s\n", code, http.StatusText(code))
+
+	headers := opt.Headers
+	if headers == nil {
+		headers = []string{"Content-Type"}
+	}
+	for _, name := range headers {
+		for _, value := range v.record.responseHeaders[http.CanonicalHeaderKey(name)] {
+			fmt.Fprintf(&buf, "%s: %s\n", http.CanonicalHeaderKey(name), value)
+		}
+	}
+
+	body, err := v.snapshotBody(opt.Masks)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > 0 {
+		buf.WriteString("\n")
+		buf.Write(body)
+		if !bytes.HasSuffix(body, []byte("\n")) {
+			buf.WriteString("\n")
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// snapshotBody returns the normalized response body. Body which is not json is returned as it is.
+// Protocol buffer messages are decoded only from protocol buffer or json body, so error messages
+// (e.g., text/plain) of protocol buffer endpoints are also returned as they are.
+func (v *Validator) snapshotBody(masks []string) ([]byte, error) {
+	body := v.record.responseBody
+	contentType := v.record.responseHeaders.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if v.responseMessage != nil && (isProtoContentType(contentType) || isJSONMediaType(mediaType)) {
+		m := v.responseMessage()
+		if err := v.responseUnmarshalFunc(body, m); err != nil {
+			return nil, err
+		}
+		b, err := protojson.Marshal(m)
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		if len(masks) > 0 {
+			return nil, fmt.Errorf("response body is not json: %s", err)
+		}
+		return body, nil
+	}
+
+	for _, mask := range masks {
+		path, err := parseFieldPath(mask)
+		if err != nil {
+			return nil, err
+		}
+		// Masks which match nothing are likely typos, and the volatile field would fail the test
+		// on the next run anyway.
+		var ok bool
+		if value, ok = maskValue(value, path); !ok {
+			return nil, fmt.Errorf("mask %q matches no field", mask)
+		}
+	}
+	return json.MarshalIndent(value, "", "  ")
+}
+
+// maskValue replaces values at the path in the generic json value with snapshotMask. It also
+// reports whether the path matches the value. Wildcards match empty arrays and objects.
+func maskValue(v interface{}, path fieldPath) (interface{}, bool) {
+	if len(path) == 0 {
+		return snapshotMask, true
+	}
+
+	var matched bool
+	mask := func(x interface{}) interface{} {
+		x, ok := maskValue(x, path[1:])
+		matched = matched || ok
+		return x
+	}
+
+	e := path[0]
+	switch v := v.(type) {
+	case map[string]interface{}:
+		switch e.kind {
+		case pathField, pathKey:
+			if x, ok := v[e.name]; ok {
+				v[e.name] = mask(x)
+			}
+		case pathWildcard:
+			matched = len(v) == 0
+			for key, x := range v {
+				v[key] = mask(x)
+			}
+		}
+	case []interface{}:
+		switch e.kind {
+		case pathIndex:
+			if e.index < len(v) {
+				v[e.index] = mask(v[e.index])
+			}
+		case pathWildcard:
+			matched = len(v) == 0
+			for i, x := range v {
+				v[i] = mask(x)
+			}
+		}
+	}
+	return v, matched
+}
+
+func writeSnapshot(path string, snapshot []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, snapshot, 0644)
+}
+
+// diffLines returns the line diff of a and b. Removed lines are prefixed with `-` and added lines
+// are prefixed with `+`.
+func diffLines(a, b string) string {
+	x, y := strings.Split(strings.TrimSuffix(a, "\n"), "\n"), strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			switch {
+			case x[i] == y[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			fmt.Fprintf(&buf, "  %s\n", x[i])
+			i++
+			j++
+		case j == len(y) || (i < len(x) && lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&buf, "- %s\n", x[i])
+			i++
+		default:
+			fmt.Fprintf(&buf, "+ %s\n", y[j])
+			j++
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,197 @@
+package httpdoc
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+)
+
+func snapshotValidator(status int, body string) *Validator {
+	v := newValidator()
+	v.record.responseStatusCode = status
+	v.record.responseHeaders = http.Header{
+		"Content-Type": []string{"application/json"},
+		"Date":         []string{"Mon, 02 Jan 2006 15:04:05 GMT"},
+	}
+	v.record.responseBody = []byte(body)
+	return v
+}
+
+func TestValidator_MatchSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpdoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defaultSnapshotDir = dir
+	defer func() {
+		defaultSnapshotDir = "testdata"
+	}()
+
+	var buf bytes.Buffer
+	tFatalf = fprintFatalFunc(&buf)
+	defer func() {
+		tFatalf = defaultFatalFunc
+	}()
+
+	opt := &SnapshotOption{Masks: []string{"id", "items[*].created_at"}}
+
+	// With FailIfMissing, missing snapshot fails the test.
+	v := snapshotValidator(http.StatusOK, `{"name":"tcnksm","id":1,"items":[{"created_at":"2017-01-01"}]}`)
+	v.MatchSnapshot(t, "users/get", &SnapshotOption{Masks: opt.Masks, FailIfMissing: true})
+	if want := `snapshot "users/get" does not exist (run tests with HTTPDOC=update to create it)`; !strings.Contains(buf.String(), want) {
+		t.Fatalf("expect %q to contain %q", buf.String(), want)
+	}
+
+	// The first run writes the snapshot.
+	buf.Reset()
+	v.MatchSnapshot(t, "users/get", opt)
+	if buf.Len() != 0 {
+		t.Fatalf("expect snapshot to be written: %s", buf.String())
+	}
+
+	got, err := ioutil.ReadFile(filepath.Join(dir, "users", "get.snapshot"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `200 OK
+Content-Type: application/json
+
+{
+  "id": "{masked}",
+  "items": [
+    {
+      "created_at": "{masked}"
+    }
+  ],
+  "name": "tcnksm"
+}
+`
+	if string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	// Masked fields don't fail the test.
+	v = snapshotValidator(http.StatusOK, `{"id":2,"name":"tcnksm","items":[{"created_at":"2017-01-02"}]}`)
+	v.MatchSnapshot(t, "users/get", opt)
+	if buf.Len() != 0 {
+		t.Fatalf("expect snapshot to match: %s", buf.String())
+	}
+
+	v = snapshotValidator(http.StatusOK, `{"id":2,"name":"deeeet","items":[]}`)
+	v.MatchSnapshot(t, "users/get", opt)
+	for _, want := range []string{
+		`response does not match snapshot "users/get"`,
+		"    \"id\": \"{masked}\",\n-   \"items\": [\n-     {\n-       \"created_at\": \"{masked}\"\n-     }\n-   ],\n-   \"name\": \"tcnksm\"\n+   \"items\": [],\n+   \"name\": \"deeeet\"\n  }\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expect %q to contain %q", buf.String(), want)
+		}
+	}
+
+	// With HTTPDOC=update, the snapshot is updated.
+	buf.Reset()
+	os.Setenv(EnvHTTPDoc, SnapshotUpdate)
+	defer os.Unsetenv(EnvHTTPDoc)
+	v.MatchSnapshot(t, "users/get", opt)
+	os.Unsetenv(EnvHTTPDoc)
+	v.MatchSnapshot(t, "users/get", opt)
+	if buf.Len() != 0 {
+		t.Fatalf("expect snapshot to be updated: %s", buf.String())
+	}
+
+	// Names which go out of the snapshot directory are rejected.
+	for _, name := range []string{"../users", "users/../../get", "/users", ""} {
+		buf.Reset()
+		v.MatchSnapshot(t, name, opt)
+		if want := "invalid snapshot name"; !strings.Contains(buf.String(), want) {
+			t.Fatalf("%q: expect %q to contain %q", name, buf.String(), want)
+		}
+	}
+
+	// Masks which match nothing fail the test.
+	buf.Reset()
+	v.MatchSnapshot(t, "users/get", &SnapshotOption{Masks: []string{"created_at"}})
+	if want := `mask "created_at" matches no field`; !strings.Contains(buf.String(), want) {
+		t.Fatalf("expect %q to contain %q", buf.String(), want)
+	}
+}
+
+func TestValidator_snapshot(t *testing.T) {
+	cases := []struct {
+		status int
+		body   string
+		opt    *SnapshotOption
+		want   string
+	}{
+		{
+			http.StatusNotFound,
+			"not found",
+			&SnapshotOption{},
+			"404 Not Found\nContent-Type: application/json\n\nnot found\n",
+		},
+		{
+			http.StatusNoContent,
+			"",
+			&SnapshotOption{Headers: []string{"date", "X-Missing"}},
+			"204 No Content\nDate: Mon, 02 Jan 2006 15:04:05 GMT\n",
+		},
+		{
+			http.StatusOK,
+			`{"users":{"a":{"id":1},"b":{"id":2}},"count":12345678901234567890}`,
+			&SnapshotOption{Headers: []string{}, Masks: []string{`users[*].id`}},
+			"200 OK\n\n{\n  \"count\": 12345678901234567890,\n  \"users\": {\n    \"a\": {\n      \"id\": \"{masked}\"\n    },\n    \"b\": {\n      \"id\": \"{masked}\"\n    }\n  }\n}\n",
+		},
+	}
+
+	for _, tc := range cases {
+		got, err := snapshotValidator(tc.status, tc.body).snapshot(tc.opt)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if string(got) != tc.want {
+			t.Fatalf("got %q, want %q", got, tc.want)
+		}
+	}
+}
+
+func TestValidator_snapshot_Proto(t *testing.T) {
+	response := &UserProtoResponse{Id: 7089, Name: "tcnksm", Active: true}
+	body, _ := response.Marshal()
+
+	v := snapshotValidator(http.StatusOK, string(body))
+	v.record.responseHeaders.Set("Content-Type", "application/protobuf")
+	v.responseUnmarshalFunc = protoUnmarshalFunc
+	v.responseMessage, _ = (&Document{}).messageFactory(func() proto.Message { return &UserProtoResponse{} }, "")
+
+	got, err := v.snapshot(&SnapshotOption{Masks: []string{"id"}})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	want := "200 OK\nContent-Type: application/protobuf\n\n{\n  \"active\": true,\n  \"id\": \"{masked}\",\n  \"name\": \"tcnksm\"\n}\n"
+	if string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	// Text error message of protocol buffer endpoint is written as it is.
+	v.record.responseStatusCode = http.StatusNotFound
+	v.record.responseHeaders.Set("Content-Type", "text/plain; charset=utf-8")
+	v.record.responseBody = []byte("user not found\n")
+	v.responseUnmarshalFunc = protoJSONUnmarshalFunc
+
+	got, err = v.snapshot(&SnapshotOption{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	want = "404 Not Found\nContent-Type: text/plain; charset=utf-8\n\nuser not found\n"
+	if string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,179 @@
+package httpdoc
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// snippetSkipHeaders is list of headers which are not included in snippets since clients set them automatically.
+var snippetSkipHeaders = []string{"Content-Length", "Host"}
+
+// snippetPart is a part of request URL or header value in snippets. If secret is true, value is
+// the name of environment variable which holds the actual value.
+type snippetPart struct {
+	value  string
+	secret bool
+}
+
+// snippetRequest is a request to render as snippets.
+type snippetRequest struct {
+	method  string
+	url     []snippetPart
+	headers [][2]snippetPart
+	body    string
+}
+
+// snippetRequest builds snippetRequest from the entry. Secret values (see Document.Secrets) are replaced
+// with environment variables whose names are upper-cased header or parameter names (e.g., `$AUTHORIZATION`).
+// Protocol buffer encoded body is sent as the json example with `Content-Type: application/json`
+// (see Entry.requestHeaders).
+func (d *Document) snippetRequest(e *Entry) *snippetRequest {
+	part := func(name string, v interface{}) snippetPart {
+		if d.isSecret(name) {
+			return snippetPart{value: strings.ToUpper(variableName(name)), secret: true}
+		}
+		return snippetPart{value: fmt.Sprint(v)}
+	}
+
+	r := &snippetRequest{
+		method: e.Method,
+		url:    []snippetPart{{value: d.baseURL() + e.Path}},
+		body:   strings.TrimRight(e.RequestExample, "\n"),
+	}
+
+	for i, p := range e.requestParams() {
+		sep := "&"
+		if i == 0 {
+			sep = "?"
+		}
+		r.url = append(r.url, snippetPart{value: sep + url.QueryEscape(p.Name) + "="})
+
+		v := part(p.Name, p.Value)
+		if !v.secret {
+			v.value = url.QueryEscape(v.value)
+		}
+		r.url = append(r.url, v)
+	}
+
+	headers := excludeData(e.requestHeaders(), snippetSkipHeaders)
+	for _, h := range headers {
+		r.headers = append(r.headers, [2]snippetPart{{value: h.Name}, part(h.Name, h.Value)})
+	}
+
+	return r
+}
+
+// curl returns runnable curl command for the entry.
+func (d *Document) curl(e Entry) string {
+	r := d.snippetRequest(&e)
+
+	var lines []string
+	line := "curl"
+	if r.method != "GET" || r.body != "" {
+		line += " -X " + r.method
+	}
+	lines = append(lines, line+" "+shellDoubleQuote(r.url...))
+
+	for _, h := range r.headers {
+		lines = append(lines, "-H "+shellDoubleQuote(snippetPart{value: h[0].value + ": "}, h[1]))
+	}
+	if r.body != "" {
+		lines = append(lines, "-d "+shellSingleQuote(r.body))
+	}
+
+	return strings.Join(lines, " \\\n  ")
+}
+
+// httpie returns runnable HTTPie command for the entry.
+func (d *Document) httpie(e Entry) string {
+	r := d.snippetRequest(&e)
+
+	lines := []string{"http " + r.method + " " + shellDoubleQuote(r.url...)}
+	for _, h := range r.headers {
+		lines = append(lines, shellDoubleQuote(snippetPart{value: h[0].value + ":"}, h[1]))
+	}
+
+	s := strings.Join(lines, " \\\n  ")
+	if r.body != "" {
+		s += " <<< " + shellSingleQuote(r.body)
+	}
+	return s
+}
+
+// goHTTP returns Go code which sends the request of the entry with net/http package.
+func (d *Document) goHTTP(e Entry) string {
+	r := d.snippetRequest(&e)
+
+	var buf bytes.Buffer
+	body := "nil"
+	if r.body != "" {
+		fmt.Fprintf(&buf, "body := strings.NewReader(%s)\n", goStringLiteral(r.body))
+		body = "body"
+	}
+
+	fmt.Fprintf(&buf, "req, err := http.NewRequest(%q, %s, %s)\n", r.method, goConcat(r.url...), body)
+	fmt.Fprintf(&buf, "if err != nil {\n\tlog.Fatal(err)\n}\n")
+	for _, h := range r.headers {
+		fmt.Fprintf(&buf, "req.Header.Set(%q, %s)\n", h[0].value, goConcat(h[1]))
+	}
+	fmt.Fprintf(&buf, "\nres, err := http.DefaultClient.Do(req)\n")
+	fmt.Fprintf(&buf, "if err != nil {\n\tlog.Fatal(err)\n}\n")
+	fmt.Fprintf(&buf, "defer res.Body.Close()")
+
+	return buf.String()
+}
+
+// shellDoubleQuote joins parts into a shell double quoted string. Secret parts are expanded
+// from environment variables.
+func shellDoubleQuote(parts ...snippetPart) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+
+	var buf bytes.Buffer
+	buf.WriteString(`"`)
+	for _, p := range parts {
+		if p.secret {
+			buf.WriteString("${" + p.value + "}")
+			continue
+		}
+		buf.WriteString(replacer.Replace(p.value))
+	}
+	buf.WriteString(`"`)
+	return buf.String()
+}
+
+// shellSingleQuote quotes the string with single quotes.
+func shellSingleQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// goConcat returns Go expression which concatenates parts. Secret parts are read from environment variables.
+func goConcat(parts ...snippetPart) string {
+	var exprs []string
+	var literal string
+	for _, p := range parts {
+		if !p.secret {
+			literal += p.value
+			continue
+		}
+		if literal != "" {
+			exprs = append(exprs, strconv.Quote(literal))
+			literal = ""
+		}
+		exprs = append(exprs, fmt.Sprintf("os.Getenv(%q)", p.value))
+	}
+	if literal != "" || len(exprs) == 0 {
+		exprs = append(exprs, strconv.Quote(literal))
+	}
+	return strings.Join(exprs, " + ")
+}
+
+// goStringLiteral returns raw string literal if possible since it's more readable for multi line body.
+func goStringLiteral(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
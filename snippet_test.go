@@ -0,0 +1,88 @@
+package httpdoc
+
+import (
+	"testing"
+)
+
+func TestDocument_Curl(t *testing.T) {
+	doc := &Document{BaseURL: "https://api.example.com"}
+
+	got := doc.curl(testExportEntries[0])
+	want := `curl -X POST "https://api.example.com/v1/user?pretty=true&token=${TOKEN}" \
+  -H "Content-Type: application/json" \
+  -H "X-Version: 2" \
+  -d '{"name": "tcnksm"}'`
+	if got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+
+	got = doc.curl(testExportEntries[2])
+	want = `curl "https://api.example.com/v1/user/1"`
+	if got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDocument_Curl_Proto(t *testing.T) {
+	doc := &Document{}
+
+	// The json example decoded from protocol buffer encoded body is sent as json.
+	got := doc.curl(Entry{
+		Method:         "POST",
+		Path:           "/v1/user",
+		RequestHeaders: []Data{{Name: "Content-Type", Value: "application/protobuf"}},
+		RequestExample: "{\n  \"id\": 7089\n}\n",
+		requestDecoded: true,
+	})
+	want := `curl -X POST "http://localhost/v1/user" \
+  -H "Content-Type: application/json" \
+  -d '{
+  "id": 7089
+}'`
+	if got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDocument_HTTPie(t *testing.T) {
+	doc := &Document{}
+
+	got := doc.httpie(testExportEntries[0])
+	want := `http POST "http://localhost/v1/user?pretty=true&token=${TOKEN}" \
+  "Content-Type:application/json" \
+  "X-Version:2" <<< '{"name": "tcnksm"}'`
+	if got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDocument_GoHTTP(t *testing.T) {
+	doc := &Document{}
+
+	got := doc.goHTTP(testExportEntries[0])
+	want := "body := strings.NewReader(`{\"name\": \"tcnksm\"}`)\n" +
+		`req, err := http.NewRequest("POST", "http://localhost/v1/user?pretty=true&token=" + os.Getenv("TOKEN"), body)
+if err != nil {
+	log.Fatal(err)
+}
+req.Header.Set("Content-Type", "application/json")
+req.Header.Set("X-Version", "2")
+
+res, err := http.DefaultClient.Do(req)
+if err != nil {
+	log.Fatal(err)
+}
+defer res.Body.Close()`
+	if got != want {
+		t.Fatalf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got, want := shellDoubleQuote(snippetPart{value: "a\"$`\\"}, snippetPart{value: "KEY", secret: true}), "\"a\\\"\\$\\`\\\\${KEY}\""; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+	if got, want := shellSingleQuote("it's"), `'it'\''s'`; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
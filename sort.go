@@ -0,0 +1,177 @@
+package httpdoc
+
+import (
+	"reflect"
+	"sort"
+)
+
+// LessFunc reports whether entry a should be written before entry b in documentation.
+// It's used for Document.SortBy.
+type LessFunc func(a, b *Entry) bool
+
+// methodOrder is the order of HTTP methods used by ByMethod. Other methods are written after them.
+var methodOrder = map[string]int{
+	"GET":     1,
+	"HEAD":    2,
+	"POST":    3,
+	"PUT":     4,
+	"PATCH":   5,
+	"DELETE":  6,
+	"OPTIONS": 7,
+}
+
+var (
+	// ByPath sorts entries by request path. Path template is used instead if it's resolved.
+	ByPath LessFunc = func(a, b *Entry) bool {
+		return a.path() < b.path()
+	}
+
+	// ByMethod sorts entries by HTTP method in the order of GET, HEAD, POST, PUT, PATCH, DELETE
+	// and OPTIONS. Other methods are sorted alphabetically after them.
+	ByMethod LessFunc = func(a, b *Entry) bool {
+		i, j := methodOrder[a.Method], methodOrder[b.Method]
+		if i == 0 || j == 0 {
+			if i != j {
+				return j == 0
+			}
+			return a.Method < b.Method
+		}
+		return i < j
+	}
+
+	// ByStatus sorts entries by response status code.
+	ByStatus LessFunc = func(a, b *Entry) bool {
+		return a.ResponseStatusCode < b.ResponseStatusCode
+	}
+
+	// ByTag sorts entries by the first tag. Entries without tags are sorted after them.
+	ByTag LessFunc = func(a, b *Entry) bool {
+		if len(a.Tags) == 0 || len(b.Tags) == 0 {
+			return len(a.Tags) > len(b.Tags)
+		}
+		return a.Tags[0] < b.Tags[0]
+	}
+)
+
+// byLess sorts entries by multiple LessFunc. If an entry is not less than the other by the first
+// function and vice versa, the next function is used.
+type byLess struct {
+	entries []Entry
+	less    []LessFunc
+}
+
+func (s byLess) Len() int      { return len(s.entries) }
+func (s byLess) Swap(i, j int) { s.entries[i], s.entries[j] = s.entries[j], s.entries[i] }
+func (s byLess) Less(i, j int) bool {
+	a, b := &s.entries[i], &s.entries[j]
+	for _, less := range s.less {
+		switch {
+		case less(a, b):
+			return true
+		case less(b, a):
+			return false
+		}
+	}
+	return false
+}
+
+// documentView is Document for templating. Its entries are de-duplicated and sorted by Document.SortBy.
+// Document methods which aggregate entries (e.g., Stats) still use all recorded entries.
+type documentView struct {
+	*Document
+
+	Entries []Entry
+}
+
+// TagGroups groups the sorted entries by tags. See Document.TagGroups.
+func (v *documentView) TagGroups() []TagGroup {
+	return tagGroups(v.Entries)
+}
+
+// view returns documentView of the document. Entries are sorted stably, so entries which are
+// equal by all Document.SortBy functions are written in the recorded order. Anchors of entries are
+// also set.
+func (d *Document) view() *documentView {
+	entries := uniqueEntries(d.Entries)
+	sort.Stable(byLess{entries: entries, less: d.SortBy})
+	for i := range entries {
+		entries[i].id = i
+	}
+	setAnchors(entries)
+	return &documentView{Document: d, Entries: entries}
+}
+
+// uniqueEntries returns entries without duplication. Entries are duplicated when the same request is
+// recorded multiple times (e.g., tests run with `-count`). Timings are ignored to compare entries.
+func uniqueEntries(entries []Entry) []Entry {
+	unique := make([]Entry, 0, len(entries))
+	var keys []Entry
+	for _, e := range entries {
+		key := e.withoutTimings()
+
+		var duplicated bool
+		for _, k := range keys {
+			if reflect.DeepEqual(k, key) {
+				duplicated = true
+				break
+			}
+		}
+		if duplicated {
+			continue
+		}
+
+		keys = append(keys, key)
+		unique = append(unique, e)
+	}
+	return unique
+}
+
+// withoutTimings returns copy of the entry whose values which change on every request are cleared.
+// Matchers are replaced with their descriptions because matchers are created on every request.
+func (e Entry) withoutTimings() Entry {
+	e.Duration = 0
+	e.TimeToFirstByte = 0
+	e.record = nil
+
+	e.PathParams = describeMatchers(e.PathParams)
+	e.RequestParams = describeMatchers(e.RequestParams)
+	e.RequestHeaders = describeMatchers(e.RequestHeaders)
+	e.RequestFields = describeMatchers(e.RequestFields)
+	e.ResponseHeaders = describeMatchers(e.ResponseHeaders)
+	e.ResponseFields = describeMatchers(e.ResponseFields)
+
+	if e.ResponseEvents != nil {
+		events := make([]Event, len(e.ResponseEvents))
+		for i, event := range e.ResponseEvents {
+			event.Offset = 0
+			events[i] = event
+		}
+		e.ResponseEvents = events
+	}
+
+	if e.WebSocket != nil {
+		session := *e.WebSocket
+		session.Messages = make([]WebSocketMessage, len(e.WebSocket.Messages))
+		for i, m := range e.WebSocket.Messages {
+			m.Offset = 0
+			session.Messages[i] = m
+		}
+		e.WebSocket = &session
+	}
+	return e
+}
+
+// describeMatchers returns copy of the data whose Matcher values are replaced with their descriptions.
+func describeMatchers(data []Data) []Data {
+	if data == nil {
+		return nil
+	}
+	described := make([]Data, len(data))
+	for i, d := range data {
+		if m, ok := d.Value.(Matcher); ok {
+			d.Value = m.String()
+		}
+		described[i] = d
+	}
+	return described
+}
@@ -0,0 +1,103 @@
+package httpdoc
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocument_View_SortBy(t *testing.T) {
+	entries := []Entry{
+		{Method: "POST", Path: "/v1/user", ResponseStatusCode: 400},
+		{Method: "DELETE", Path: "/v1/user", ResponseStatusCode: 200, Tags: []string{"user"}},
+		{Method: "PURGE", Path: "/v1/cache", ResponseStatusCode: 200},
+		{Method: "GET", Path: "/v1/user", ResponseStatusCode: 200, Tags: []string{"admin"}},
+		{Method: "POST", Path: "/v1/user", ResponseStatusCode: 200},
+	}
+
+	cases := []struct {
+		sortBy []LessFunc
+		want   []string
+	}{
+		{
+			nil,
+			[]string{"POST /v1/user 400", "DELETE /v1/user 200", "PURGE /v1/cache 200", "GET /v1/user 200", "POST /v1/user 200"},
+		},
+		{
+			[]LessFunc{ByPath, ByMethod, ByStatus},
+			[]string{"PURGE /v1/cache 200", "GET /v1/user 200", "POST /v1/user 200", "POST /v1/user 400", "DELETE /v1/user 200"},
+		},
+		{
+			[]LessFunc{ByTag},
+			[]string{"GET /v1/user 200", "DELETE /v1/user 200", "POST /v1/user 400", "PURGE /v1/cache 200", "POST /v1/user 200"},
+		},
+		{
+			[]LessFunc{func(a, b *Entry) bool { return len(a.Method) < len(b.Method) }},
+			[]string{"GET /v1/user 200", "POST /v1/user 400", "POST /v1/user 200", "PURGE /v1/cache 200", "DELETE /v1/user 200"},
+		},
+	}
+
+	for i, tc := range cases {
+		doc := &Document{Entries: entries, SortBy: tc.sortBy}
+
+		var got []string
+		for _, e := range doc.view().Entries {
+			got = append(got, e.Method+" "+e.Path+" "+strconv.Itoa(e.ResponseStatusCode))
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("#%d: got %v, want %v", i, got, tc.want)
+		}
+	}
+
+	// Recorded entries must not be modified.
+	if entries[0].ResponseStatusCode != 400 {
+		t.Fatal("expect recorded entries not to be sorted")
+	}
+}
+
+func TestUniqueEntries(t *testing.T) {
+	entries := []Entry{
+		{Method: "GET", Path: "/v1/user", ResponseExample: "a", Duration: time.Millisecond,
+			ResponseEvents: []Event{{Data: "x", Offset: time.Millisecond}}},
+		{Method: "GET", Path: "/v1/user", ResponseExample: "a", Duration: time.Second,
+			ResponseEvents: []Event{{Data: "x", Offset: time.Second}}},
+		{Method: "GET", Path: "/v1/user", ResponseExample: "b"},
+	}
+
+	got := uniqueEntries(entries)
+	if len(got) != 2 {
+		t.Fatalf("got %d entries, want 2", len(got))
+	}
+	if got[0].Duration != time.Millisecond || got[1].ResponseExample != "b" {
+		t.Fatalf("expect the first entry to be kept: %#v", got)
+	}
+	if entries[1].ResponseEvents[0].Offset != time.Second {
+		t.Fatal("expect recorded entries not to be modified")
+	}
+}
+
+func TestDocument_Generate_Unique(t *testing.T) {
+	doc := &Document{
+		ShowStats: true,
+		Entries: []Entry{
+			{Method: "GET", Path: "/v1/user", ResponseStatusCode: 200, Duration: time.Millisecond},
+			{Method: "GET", Path: "/v1/user", ResponseStatusCode: 200, Duration: 2 * time.Millisecond},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := doc.generate(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if got := strings.Count(buf.String(), "## [200] GET /v1/user"); got != 1 {
+		t.Fatalf("expect entry to be written once, got %d", got)
+	}
+	// Stats are aggregated from all recorded entries.
+	if want := "| GET /v1/user | 2 |"; !strings.Contains(buf.String(), want) {
+		t.Fatalf("expect %q to contain %q", buf.String(), want)
+	}
+}
@@ -89,7 +89,7 @@ func tmplApiBlueprintTmpl() (*asset, error) {
 	return a, nil
 }
 
-var _tmplDocMdTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x53\xc1\x6e\xd4\x30\x10\xbd\xcf\x57\x8c\x94\x03\x70\xd8\xf4\x8e\x56\x95\x50\x5b\x04\x07\xd0\xaa\x54\x5c\x2a\xa4\x4c\xe3\xd9\xc6\x90\xd8\xc1\x9e\x40\xab\x4d\xfe\x1d\xd9\xde\xad\x1c\x6d\xf7\x42\x9b\x9c\x66\x9e\xc7\xf3\xde\x9b\x8c\x0b\xfc\xb0\xf9\x8c\xca\xd6\x00\x37\x8d\xf6\xa8\xfd\x01\x18\x3a\x36\x42\xa2\xad\xc1\xad\x75\xb8\xdb\x61\xf9\x95\x3a\xc6\x69\x2a\xf1\x50\x7a\xcf\x86\x1d\x09\x2b\xbc\x7b\xc4\xaa\x11\xe9\x95\xad\xab\x12\x2f\xad\x79\x23\xc8\x4a\x4b\x38\x68\xc8\xa8\x12\xa0\x28\xf0\x86\xee\x5a\x46\xbb\xc5\xda\x1a\x61\x23\x1e\x60\xb7\x43\x47\xe6\x9e\xb1\xbc\x32\xe2\x34\x7b\x5c\x4d\x13\xac\xf0\xf6\x36\x30\x5e\xb3\xef\xad\xf1\xfc\x4d\x48\x06\x7f\x61\x55\xe0\xff\x11\xc5\x7c\x61\x69\xac\xc2\x69\x8a\xd9\x86\xa4\x09\x47\x6f\x8b\x93\xd7\x56\xd9\xad\x11\x5b\xfb\x97\xdd\x01\x8d\xb7\x47\xf4\xe2\x74\xef\x5b\xf2\x4d\x56\xf0\x2e\x48\x64\x13\x98\x4e\xa9\x2d\x0a\xfc\x2f\xb5\xb1\x5f\x79\xc9\xbe\x76\xba\x8f\x93\x0e\x58\x51\x14\x78\xcd\xbf\x07\xf6\x12\x0b\xf4\x36\x74\x8e\xf9\x86\x1c\x75\x89\x33\x86\x2c\xec\x3c\xc0\x88\xf1\xcf\xe0\x88\xdf\xa9\x1d\x62\x90\x37\x1d\x61\xc4\x55\xf8\x70\xc4\xf7\xf3\x20\x25\x99\xad\x63\xa2\x31\xff\xf5\x98\xb2\x44\xf3\x94\xce\x1d\xa4\x7e\x69\x62\xf3\xd1\x65\x4e\x3e\x31\x29\x76\x89\x61\x1f\x2f\xe1\x23\xa7\x79\x91\x11\x38\xe9\xe4\xa3\xe6\x56\x25\x86\x3d\x82\xdb\x08\x2d\xe1\x27\x23\x5b\xc8\xce\xd5\x03\x75\x7d\xcb\x33\x3f\x9c\x30\x80\xb5\x62\x21\xdd\xfa\x73\x58\xfb\xa1\xeb\xc8\x3d\x9e\x5f\xb4\xba\xfe\x85\x62\x91\x1f\x7a\x32\x0a\x6b\xab\xb8\x5c\x9f\x1d\x8e\x01\xaa\xaa\xfa\x49\x7f\x28\x29\x81\xf4\x4c\x66\x4c\xd3\x14\x6a\x00\xd6\x67\x4f\xdd\x33\x75\xe9\x35\xa4\x77\x95\x69\x4d\xc0\xf2\x5b\x74\xcc\xf3\xfa\x73\x4f\x1c\xb3\x3d\x4a\xd0\x32\x8b\x74\x44\xb7\x94\xa3\xf9\x2a\xed\x2d\xbd\xfa\x2e\xcd\xb9\x9e\x59\xa6\x67\xb5\xfe\x0b\x00\x00\xff\xff\x2f\x57\xc7\x87\xf8\x06\x00\x00")
+var _tmplDocMdTmpl = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb5\x19\xdb\x6e\xdb\x36\xf4\x5d\x5f\x41\x24\x29\x96\x18\xb5\xba\x3d\xf4\x61\x46\x56\xa0\x71\x92\x2e\x45\x2f\x69\xec\x6e\x0f\x45\x01\x33\x12\x6d\x33\x95\x25\x95\xa2\xd2\x78\xb1\xfe\x7d\x87\x87\xa4\x44\xca\x92\xd3\xad\x69\x10\x44\xe4\xe1\xe1\xb9\x5f\x48\xe6\xfe\x9e\x48\xb6\xca\x13\x2a\x19\xd9\x5b\x32\x1a\x33\xb1\x47\x42\x52\x55\x41\x70\xef\x2e\x45\x59\x2a\x59\x2a\x0b\x67\x51\xd0\x74\xc1\x48\x78\x96\x4a\xc1\x59\x41\x86\x00\xf6\xb6\x00\xba\x58\x6b\x7c\x80\xb3\x34\x26\x6d\x8c\x42\xd2\x9a\xe2\xfd\xfd\xd0\x63\x77\xcb\x04\x5d\xb0\x86\xdd\x90\xc4\x6c\xce\x53\x47\x4a\xc5\x70\x9f\xbc\xbc\xbc\x20\x71\x16\x05\xc1\x74\xc9\x0b\x02\xbf\x06\x50\xae\x80\x3f\x95\x3c\x4b\xc9\x3c\x13\x04\xf8\x86\xef\xe8\x8a\x01\xb5\x90\x58\xd4\x05\x4b\x81\x8b\x64\x31\xb9\x5e\x93\xd9\x52\xca\x1c\x36\xce\x42\x72\x9a\xa5\xbf\x48\xc2\x62\x2e\xd5\xc2\x92\xa6\x71\x88\x12\x28\x1d\x86\x46\x7b\x2b\x8d\xcc\x22\x2d\xca\x90\x7c\x52\x4c\xa6\x5c\x26\x8a\xcb\xe7\x43\x98\x2d\x05\x9b\xa3\x06\x47\x30\xe1\x30\x9c\x94\xab\x15\x15\x6b\x80\x90\x21\xca\xd4\x00\x6a\x1b\x19\xd4\x53\x96\x0b\x16\xa1\x74\x80\x7d\x18\xd7\xd3\x23\xcf\x9a\x3d\x32\xa5\x59\xcc\xf6\xac\x4f\xc2\x8b\x34\x06\x6b\xc0\x0e\xcd\xf4\x92\xca\xa5\xd9\xde\x72\x22\x72\x3f\x68\xf0\x09\xf1\x1c\x86\xca\xb6\x1d\x3a\xb4\x44\xc6\x4b\x9e\xc4\x82\xa5\x7a\xdd\xdb\xa5\xc5\xd9\xde\xd9\x25\xfd\x9c\xb3\x24\x2e\x6a\xe1\x0f\x24\x19\xfd\x41\x10\x28\xe9\x35\xd8\x36\xc4\x95\x0d\x41\x77\x92\x0d\x99\xae\x73\xfc\x5e\xb1\xaf\x25\x17\x60\xae\x0d\xf9\x8b\x26\x25\xc2\x4e\x59\x11\x09\x9e\x63\x18\x6c\xb4\x61\x0f\x64\x78\x76\x47\x41\x36\xe5\x25\x62\x87\x9b\x96\xf9\x01\x6b\x9c\xa5\x85\x14\x94\x43\xd4\x2b\x4c\x77\xda\x60\x83\x1c\x43\xf5\x03\xbc\x46\xee\x77\x38\x1c\xd5\xc3\x06\x86\x93\x2e\x31\x46\xce\xe2\x6e\x31\x1a\x4a\x2e\x76\xe3\x4a\xd8\x71\x95\x7d\x2b\x8c\x8d\x3c\xe7\x3b\x39\x80\x5b\xbf\x71\x08\x83\xf0\x94\x4a\xaa\x20\x18\xbc\xca\x94\x88\xa8\xd7\xce\x33\xb1\xa2\x18\x07\x2a\x9a\x6d\x20\x6b\x96\x04\xa9\xab\x48\xad\x0d\x5f\x55\x6b\x56\xb4\x10\x42\xed\x8b\x7a\xea\x7a\xc4\xc8\xb1\x65\x0d\x85\xe7\x4c\x1f\x36\x0a\xc0\x6f\x32\x9e\x92\x36\xdc\xdb\xc9\x92\x02\xc9\x81\x20\xf6\xb7\x8b\xf9\x77\xb0\x6b\xd1\xdd\xca\xc5\xbe\xc8\x6e\x8a\x28\xd6\xae\x7d\x32\xc5\x80\xce\xe6\xc4\xae\x20\xba\xb6\xfd\x94\x2e\x5e\x89\xac\xcc\xeb\xca\x6a\xfc\xcb\x9f\x92\x83\x85\x5a\x50\x69\x11\x36\x52\xf2\x96\x10\xfb\x40\x1f\x7d\x4a\x17\x7e\xc5\x86\x74\x94\x82\xb5\x93\xfe\xbb\xd3\xd5\x0e\x95\x35\x3d\xc9\x1e\x87\xae\x56\xfe\x65\x29\x97\x93\x68\xc9\x56\x48\x46\x99\x4a\x41\xc0\x42\x3c\xc2\x9a\x1e\xd8\xfc\x37\xe9\xbf\x21\x63\x08\x40\xb5\x4e\x93\x76\xda\x9b\x14\x25\xf5\xc7\x4d\x46\x3f\x9f\x9c\x8a\xe8\x24\x50\x9d\x33\x6e\x8e\x98\xd9\x9b\x4c\x0b\xd4\x1f\xe0\x81\x1b\x2b\x96\x78\x5d\xe5\x27\x51\x96\x6b\x1d\xcd\x08\x82\x61\xe6\x30\x9d\x29\x4d\x71\xa9\x47\xad\x5d\x3a\x4c\x32\x01\xfd\xc2\x10\xee\xd3\x67\xa7\xbc\x7d\x83\xde\x10\x37\x4d\xdf\xc4\xb7\xdb\x10\x11\xad\xd5\xd8\x14\xda\x0b\x32\x18\x34\xa0\xc1\xa0\x2e\x2a\x10\x36\x11\x5b\xe9\xd2\x35\x22\x25\x84\x1b\x1a\xc6\x5f\x98\x11\x0e\xa9\x09\x27\x82\x5a\xb4\x30\xf0\x53\xd1\xeb\xbb\x0a\x84\x2c\x9c\xc6\x3b\x18\x74\xec\x80\x23\x43\xf8\x3e\x57\xa7\x03\xb0\xcb\xc5\x29\xb0\xcd\x4a\xc9\xd0\x72\x2a\xa3\x0a\x20\xc0\xd3\x48\xd9\x98\x45\xa5\xe0\x72\x0d\x18\x97\x63\x97\xa5\xbb\xbb\xaa\xea\x19\xb9\x38\x1d\x69\x45\x7c\x84\x19\x21\x41\xfb\x08\xd0\xf0\xac\x2a\x1c\x9b\x9d\x2e\xbc\xbd\x4f\xe7\x8f\x92\x05\xf6\x5c\x8e\x47\xb6\x48\x4f\xb2\x52\x44\xec\xe3\xd5\x1b\x80\x7f\x42\x2a\xe7\x65\x92\x58\x22\x78\x5a\xf1\x70\x8e\x9a\x8a\xb9\x85\x5d\xf3\x3b\x9c\xe9\x06\x93\x97\xd2\xa4\xc5\xec\x88\x08\x26\x4b\x91\x16\x44\x2f\xbe\x2f\xa5\xb7\x7a\xcb\xa9\x3e\x83\x88\x0c\x0a\x42\x96\x60\x3d\xdd\xd2\x1c\x4d\x5c\x55\xea\x83\x1a\x38\xb5\x4f\x42\x3d\x53\x95\xcf\xa0\x38\xd5\xef\x29\xa9\xc9\xcc\xf0\xe0\x80\x95\xaf\x91\xb6\x83\x8f\x76\x62\x55\xe1\xd7\x58\xd7\xc2\xba\x3c\x52\x7b\xbb\xaa\xfc\x82\xd4\x96\xb2\x40\x19\x1d\xf4\x1d\x72\x16\xa1\x2e\x75\x66\xae\xd0\x8a\xa6\x30\x90\xc3\x02\x87\x2e\x87\x1b\xc5\x01\x8b\x02\x70\x71\x91\xcd\xf6\x9b\x2e\x2e\x88\xef\xda\xe3\x68\x3b\xaf\x5d\x95\x5b\x49\xd1\x2a\x14\x01\x36\x18\xd5\xfa\x59\x21\xeb\xdc\x56\x27\xcb\x4b\x2a\xe8\x4a\xd7\x1b\x3c\x68\xe6\x6a\xce\x24\x13\x45\xe0\x9c\xda\xba\x8f\x68\x5b\x45\xad\x55\xae\x9d\xe2\xd6\x62\xd5\x55\xda\x1e\x38\x7a\xf4\xaa\x6a\x0f\x35\xa0\x99\xa7\xcc\x4f\xd1\x63\x9b\xd1\x0f\xa9\xd2\xe8\xd4\xd2\xe4\x4f\xbc\x36\x69\x0e\x66\xfc\x33\xf4\x70\xd9\x3c\x8a\x4f\x8c\x26\xba\xae\xbd\x12\x34\x5f\x7e\x78\x83\xd4\xed\xd8\x69\xca\x41\x30\x9b\xcd\x16\x0a\xfe\x35\xc1\x98\xfd\x50\x32\xac\xf1\x0a\xde\xae\xf2\x9a\xe2\xeb\xc9\xfb\x77\xb6\x72\xab\xf1\x50\x4d\x8c\xe5\xb0\x30\x0b\xad\x97\xae\x0d\x6f\x99\x5c\x66\x98\x52\xe4\x90\xc7\xa6\x60\xe8\xfa\xed\x14\xcc\x34\x93\x7c\x6e\x0a\x43\x7b\x5f\xbb\xa2\x9c\x50\x19\xa9\xcb\x18\xf4\x31\x72\xad\xc6\xbe\xda\xdd\x51\x79\x8e\x57\x24\x5c\x30\x10\x7d\x41\x2a\x5a\xb7\x76\x7b\x95\x6a\xed\xdb\x32\xae\x43\xda\x9e\x84\x5d\xda\x4c\xc3\x82\xe0\x38\x66\x92\xf2\xa4\x78\x11\x1c\x17\xba\x7f\xbe\x18\x27\x3c\xfa\x02\x67\x3f\x40\xca\xe1\xa2\x0c\x27\xd9\x98\x85\xc7\xcf\xec\x32\x7a\xe4\x86\xde\x52\xed\xe6\x40\x77\x70\x8f\x93\xf5\xce\xf1\xb3\x9a\xfa\x96\x74\x60\x51\x12\xfe\xcd\xae\x27\x59\xf4\x85\x49\x14\x6e\x0a\x8e\xe5\x12\xe9\x5f\xd3\x62\xa9\x10\xa1\xd8\x26\xfa\xc1\x00\x09\xb6\x4f\xc3\x57\xac\xc8\xe1\x20\xcf\x1c\x95\x35\xe0\xe7\xa7\xc6\x36\x9f\xc7\xcc\x0d\x57\x17\x2f\x34\x34\xe8\xc1\xd8\xf0\x76\x3e\x90\x79\x75\xe0\x9e\x09\x91\x19\x5d\xec\x1a\x43\x90\x32\xdd\x5b\x56\x14\x74\xa1\xce\xac\xd8\x02\xe0\x78\x0e\x61\x81\x36\x1b\x79\x27\xef\xfa\xe3\xbe\x45\x34\x84\xb5\x1d\x2c\xb1\xda\x30\xe6\xfd\xc2\xcc\x90\x74\x9f\x7d\x76\xa5\xbc\x73\x5e\x42\x9e\x7e\x0d\x40\x65\x94\x2e\x5a\x74\x50\x89\xa6\x3c\x5d\x10\x57\x39\xbc\x3b\x9b\x48\x68\xee\xfb\x43\xb2\xad\x66\xd0\x92\xd5\x28\xa6\x49\x3a\x00\x5f\x53\xef\x86\x3e\x33\x57\x70\xe7\x40\xb3\x09\xfa\x34\x75\x43\xe2\xec\x56\x5d\x2d\xfd\x90\x60\xb7\xfa\xba\xb9\x21\xfb\xc0\x0a\x31\xe0\x0b\x95\xcc\x55\x6a\xe4\xbd\x6a\x74\xf9\xaa\x83\x45\xf3\xe8\x70\xd7\x28\xe6\xc7\x39\x16\x4c\x18\xa3\x46\x9a\x1d\xc9\x52\x96\xa8\x1b\x84\x2a\xad\xbb\x3b\xb3\x5f\x06\x9a\xd9\x4a\x1b\xaf\x70\x1f\x27\xeb\x55\x6b\xda\xc2\x5c\x01\xf6\x3d\x21\x51\x0e\x0e\x57\x10\x93\x6b\x83\x81\x7e\xf0\x30\x6d\xe5\xa8\xab\x8a\x5d\xd2\x75\x92\xd1\xb8\xdd\x5c\x76\x3a\xc2\xab\xad\xd6\x13\x8f\x5d\x5c\x7d\x5e\x1d\xd5\x35\x78\xf0\x01\xd1\x3c\xcc\xba\x17\xa8\x65\xf6\x6d\xa2\xa0\xf6\x6a\x77\xc9\xc4\x5c\xbd\x0e\xc1\x59\x59\x45\xd1\x59\x1a\xe7\x19\xc7\x20\x1a\x67\x25\x7e\xdf\x42\x47\x83\xfc\x7f\xfe\xab\xfa\xfb\xfb\x73\x05\xa1\x77\xe6\xef\x74\x7a\x7e\x62\x86\xc2\x1a\xa2\xe0\xff\xb0\xa6\xac\x36\x37\xf6\x11\x7e\xfb\xfe\x0e\x1b\x14\xff\x67\xe4\x5f\x85\x6b\xd9\x6d\xa6\x99\x86\xec\x16\x94\x3a\xe3\x9a\xdb\x9a\x41\xf0\xce\xc8\x36\x95\x4b\xfd\x44\x6a\x08\x72\xe7\x0d\x40\x29\xdd\x4c\x40\xf7\x06\x8d\xde\x79\x93\x29\x5f\xb1\x69\x76\xce\x45\x21\x4f\xd6\x92\x79\x6b\xd6\x95\x13\x65\x18\x58\xb8\x06\x84\xa2\xa7\x0d\xf4\x3f\x39\xd9\x87\xf4\x96\x33\xc7\x66\xc1\x2f\x8d\x1e\x14\xbc\x6c\xe7\x41\x70\xea\x3d\xa9\x5b\xb2\x78\x29\xc9\x05\x38\x7e\x4e\xf6\x9e\x84\xbf\xcd\xa1\x9f\x40\x64\x44\xfa\x72\xfe\xc4\xe9\x1f\x78\x67\xb5\x1e\xc0\x49\x13\x2a\x4e\x2b\xdd\xf6\x9c\xe3\x34\xbd\xcd\x7d\x7a\xb4\x4e\x70\x1d\xe2\x9d\xc3\x20\xbc\xa2\x4c\xc4\x2c\x76\x8b\x66\x5f\x75\x31\xbd\xc0\x84\xb2\x15\xd6\x09\x6d\xdd\x91\xaf\xed\x1b\x33\x8f\xf1\x15\x43\x77\x52\x00\x7d\x4c\x6f\xb7\x80\x7e\x3c\x8f\xfa\x03\x76\xe4\x3f\xe4\x3c\xf0\x26\x55\x0b\xe5\x5a\xa3\x11\xaf\x7e\x89\xb5\x86\xe8\x78\x95\x35\x82\x56\xd5\x33\xbc\xdf\xdb\xee\xaf\x11\xf4\x73\xaa\xab\xd0\x4e\xcb\x75\x0e\xbb\x23\x92\xab\xa2\xbb\xb7\xfd\xef\xa2\xff\xf6\xcf\xa7\xff\xf1\x4f\xa4\x3e\x81\xf2\x3a\x3d\xda\x0f\x56\x9f\x4e\xa8\x2e\xbf\x28\xf3\xe7\x43\xfc\x84\xab\xf8\xe8\x47\xfe\xff\xd5\x08\xf1\x2f\x78\x0a\x6a\xca\x7c\x1b\x00\x00")
 
 func tmplDocMdTmplBytes() ([]byte, error) {
 	return bindataRead(
@@ -104,7 +104,7 @@ func tmplDocMdTmpl() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "tmpl/doc.md.tmpl", size: 1784, mode: os.FileMode(420), modTime: time.Unix(1496894277, 0)}
+	info := bindataFileInfo{name: "tmpl/doc.md.tmpl", size: 7036, mode: os.FileMode(420), modTime: time.Unix(1792353300, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -0,0 +1,85 @@
+package httpdoc
+
+import (
+	"sort"
+	"time"
+)
+
+// Stats is aggregated performance statistics of an endpoint. Normally, you don't need to modify this.
+// All fields are exported just for templating.
+type Stats struct {
+	// Method is HTTP method.
+	Method string
+
+	// Path is request path or path template if it's resolved.
+	Path string
+
+	// Operation is the operation of the endpoint, e.g., `query GetUser`. See Entry.Operation.
+	Operation string
+
+	// Count is the number of recorded entries of this endpoint.
+	Count int
+
+	// Min, P50, P95 and Max are statistics of Entry.Duration.
+	Min time.Duration
+	P50 time.Duration
+	P95 time.Duration
+	Max time.Duration
+
+	// MaxTimeToFirstByte is the max of Entry.TimeToFirstByte.
+	MaxTimeToFirstByte time.Duration
+
+	// MaxResponseSize is the max of Entry.ResponseSize.
+	MaxResponseSize int
+}
+
+type byDuration []time.Duration
+
+func (d byDuration) Len() int           { return len(d) }
+func (d byDuration) Less(i, j int) bool { return d[i] < d[j] }
+func (d byDuration) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+
+// Stats returns performance statistics of each endpoint (method, path and operation). When an
+// endpoint is called many times in tests, this can be used as a cheap regression signal. Stats are
+// sorted by the order of first appearance.
+func (d *Document) Stats() []Stats {
+	groups := groupEntries(d.Entries)
+	stats := make([]Stats, 0, len(groups))
+	for _, group := range groups {
+		s := Stats{
+			Method:    group[0].Method,
+			Path:      group[0].path(),
+			Operation: group[0].Operation,
+			Count:     len(group),
+		}
+
+		durations := make(byDuration, 0, len(group))
+		for _, e := range group {
+			durations = append(durations, e.Duration)
+			if e.TimeToFirstByte > s.MaxTimeToFirstByte {
+				s.MaxTimeToFirstByte = e.TimeToFirstByte
+			}
+			if e.ResponseSize > s.MaxResponseSize {
+				s.MaxResponseSize = e.ResponseSize
+			}
+		}
+		sort.Sort(durations)
+
+		s.Min = durations[0]
+		s.P50 = percentile(durations, 50)
+		s.P95 = percentile(durations, 95)
+		s.Max = durations[len(durations)-1]
+
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// percentile returns the p-th percentile of the sorted durations by nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
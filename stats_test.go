@@ -0,0 +1,68 @@
+package httpdoc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDocument_Stats(t *testing.T) {
+	doc := &Document{}
+	for i := 1; i <= 20; i++ {
+		doc.Entries = append(doc.Entries, Entry{
+			Method:          "GET",
+			Path:            "/v1/user",
+			Duration:        time.Duration(i) * time.Millisecond,
+			TimeToFirstByte: time.Duration(i) * time.Microsecond,
+			ResponseSize:    i,
+		})
+	}
+	doc.Entries = append(doc.Entries, Entry{
+		Method:   "POST",
+		Path:     "/v1/user",
+		Duration: time.Second,
+	})
+
+	got := doc.Stats()
+	if len(got) != 2 {
+		t.Fatalf("got %d stats, want 2", len(got))
+	}
+
+	want := Stats{
+		Method:             "GET",
+		Path:               "/v1/user",
+		Count:              20,
+		Min:                1 * time.Millisecond,
+		P50:                10 * time.Millisecond,
+		P95:                19 * time.Millisecond,
+		Max:                20 * time.Millisecond,
+		MaxTimeToFirstByte: 20 * time.Microsecond,
+		MaxResponseSize:    20,
+	}
+	if got[0] != want {
+		t.Fatalf("got %+v, want %+v", got[0], want)
+	}
+
+	if got[1].P50 != time.Second || got[1].P95 != time.Second {
+		t.Fatalf("got %+v", got[1])
+	}
+}
+
+func TestDocument_Generate_Stats(t *testing.T) {
+	doc := &Document{
+		ShowStats: true,
+		Entries: []Entry{
+			{Method: "GET", Path: "/v1/user", Duration: 3 * time.Millisecond, ResponseSize: 42},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := doc.generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := buf.String(), "| GET /v1/user | 1 | 3ms | 3ms | 3ms | 3ms | 0s | 42 bytes |"; !strings.Contains(got, want) {
+		t.Fatalf("expect %q to contain %q", got, want)
+	}
+}
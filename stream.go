@@ -0,0 +1,176 @@
+package httpdoc
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// streamMediaTypes is list of media types which are recorded as streaming response.
+var streamMediaTypes = map[string]bool{
+	"text/event-stream":       true,
+	"application/x-ndjson":    true,
+	"application/ndjson":      true,
+	"application/jsonl":       true,
+	"application/x-jsonl":     true,
+	"application/stream+json": true,
+}
+
+// Event is an event of streaming response, e.g., an event of Server-Sent Events (`text/event-stream`)
+// or a line of newline-delimited JSON (`application/x-ndjson`). Normally, you don't need to modify this.
+// All fields are exported just for templating.
+type Event struct {
+	// Index is the position of the event in the response (0-origin).
+	Index int
+
+	// ID is `id` field of Server-Sent Events.
+	ID string
+
+	// Name is `event` field of Server-Sent Events. It's empty for newline-delimited JSON.
+	Name string
+
+	// Data is event data.
+	Data string
+
+	// Offset is time from handler is called until the event is written. It's not written in
+	// documentation because it changes on every run.
+	Offset time.Duration
+}
+
+// isStreamMediaType reports whether the response with the given Content-Type is streaming response.
+func isStreamMediaType(v string) bool {
+	mediaType, _, err := mime.ParseMediaType(v)
+	if err != nil {
+		return false
+	}
+	return streamMediaTypes[mediaType]
+}
+
+// parseEvents splits streaming response body into events. writes is used to know when each event is written.
+func parseEvents(contentType string, body []byte, writes []write, startedAt time.Time) []Event {
+	if !isStreamMediaType(contentType) {
+		return nil
+	}
+
+	offset := func(pos int) time.Duration {
+		return writeOffset(writes, pos, startedAt)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "text/event-stream" {
+		return parseServerSentEvents(body, offset)
+	}
+
+	var events []Event
+	var pos int
+	for _, line := range bytes.SplitAfter(body, []byte("\n")) {
+		pos += len(line)
+		data := strings.TrimSpace(string(line))
+		if data == "" {
+			continue
+		}
+		events = append(events, Event{
+			Index:  len(events),
+			Data:   data,
+			Offset: offset(pos),
+		})
+	}
+	return events
+}
+
+// writeOffset returns time from startedAt until the data until the given position is written.
+func writeOffset(writes []write, pos int, startedAt time.Time) time.Duration {
+	for _, w := range writes {
+		if w.end >= pos {
+			return w.at.Sub(startedAt)
+		}
+	}
+	return 0
+}
+
+// parseServerSentEvents parses body in Server-Sent Events format.
+// See https://html.spec.whatwg.org/multipage/server-sent-events.html#event-stream-interpretation
+func parseServerSentEvents(body []byte, offset func(pos int) time.Duration) []Event {
+	var (
+		events []Event
+		event  Event
+		data   []string
+		pos    int
+	)
+	for _, line := range bytes.SplitAfter(body, []byte("\n")) {
+		pos += len(line)
+		text := strings.TrimRight(string(line), "\r\n")
+
+		// Blank line dispatches the event.
+		if text == "" {
+			if len(data) > 0 {
+				event.Index = len(events)
+				event.Data = strings.Join(data, "\n")
+				event.Offset = offset(pos)
+				events = append(events, event)
+			}
+			event, data = Event{}, nil
+			continue
+		}
+
+		// Line starts with colon is a comment.
+		if strings.HasPrefix(text, ":") {
+			continue
+		}
+
+		field, value := text, ""
+		if i := strings.Index(text, ":"); i >= 0 {
+			field, value = text[:i], strings.TrimPrefix(text[i+1:], " ")
+		}
+
+		switch field {
+		case "event":
+			event.Name = value
+		case "data":
+			data = append(data, value)
+		case "id":
+			event.ID = value
+		}
+	}
+	return events
+}
+
+// ResponseEvent validates fields of the index-th event (0-origin) of streaming response. The event data is
+// unmarshaled to the given struct. Validated fields are documented as `events[index].Target`.
+func (v *Validator) ResponseEvent(t *testing.T, index int, cases []TestCase, event interface{}) {
+	if index < 0 || index >= len(v.record.responseEvents) {
+		tFatalf(t, "response event %d is not found: response has %d events", index, len(v.record.responseEvents))
+		return
+	}
+	v.validateEvent(t, fmt.Sprintf("events[%d]", index), v.record.responseEvents[index], cases, event)
+}
+
+// ResponseEventByName validates fields of the first event which has the given name (`event` field of
+// Server-Sent Events). The event data is unmarshaled to the given struct. Validated fields are documented
+// as `events["name"].Target`.
+func (v *Validator) ResponseEventByName(t *testing.T, name string, cases []TestCase, event interface{}) {
+	for _, e := range v.record.responseEvents {
+		if e.Name == name {
+			v.validateEvent(t, fmt.Sprintf("events[%q]", name), e, cases, event)
+			return
+		}
+	}
+	tFatalf(t, "response event %q is not found", name)
+}
+
+func (v *Validator) validateEvent(t *testing.T, prefix string, e Event, cases []TestCase, event interface{}) {
+	if err := defaultUnmarshalFunc([]byte(e.Data), event); err != nil {
+		tFatalf(t, "Failed to unmarshal response event %s: %s", prefix, err)
+		return
+	}
+
+	var fields []Data
+	v.validateFields(t, cases, event, &fields)
+	for _, f := range fields {
+		f.Name = prefix + "." + f.Name
+		v.responseFields = append(v.responseFields, f)
+	}
+}
@@ -0,0 +1,147 @@
+package httpdoc
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Progress struct {
+	Step  int    `json:"step"`
+	State string `json:"state"`
+}
+
+func TestRecord_ServerSentEvents(t *testing.T) {
+	doc := &Document{}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "id: 1\nevent: progress\ndata: {\"step\": 1, \"state\": \"running\"}\n\n")
+		w.(http.Flusher).Flush()
+		fmt.Fprint(w, "id: 2\nevent: done\ndata: {\"step\": 2,\ndata: \"state\": \"finished\"}\n\n")
+	})
+
+	ts := httptest.NewServer(Record(handler, doc, &RecordOption{
+		WithValidate: func(validator *Validator) {
+			validator.ResponseEvent(t, 0, []TestCase{
+				NewTestCase("Step", 1, "first step"),
+			}, &Progress{})
+			validator.ResponseEventByName(t, "done", []TestCase{
+				NewTestCase("State", "finished", "final state"),
+			}, &Progress{})
+		},
+	}))
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/v1/jobs/1/events")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	res.Body.Close()
+
+	entry := doc.Entries[0]
+	if got, want := len(entry.ResponseEvents), 2; got != want {
+		t.Fatalf("got %d events, want %d", got, want)
+	}
+
+	want := []Event{
+		{Index: 0, ID: "1", Name: "progress", Data: `{"step": 1, "state": "running"}`},
+		{Index: 1, ID: "2", Name: "done", Data: "{\"step\": 2,\n\"state\": \"finished\"}"},
+	}
+	for i, e := range entry.ResponseEvents {
+		if e.Offset <= 0 || e.Offset > entry.Duration {
+			t.Fatalf("events[%d]: unexpected offset %s", i, e.Offset)
+		}
+		e.Offset = 0
+		if e != want[i] {
+			t.Fatalf("events[%d]: got %#v, want %#v", i, e, want[i])
+		}
+	}
+
+	wantFields := []Data{
+		{Name: `events["done"].State`, Value: "finished", Description: "final state", Schema: Schema{Type: "string", Required: true}},
+		{Name: "events[0].Step", Value: 1, Description: "first step", Schema: Schema{Type: "integer", Required: true}},
+	}
+	if got := fmt.Sprint(entry.ResponseFields); got != fmt.Sprint(wantFields) {
+		t.Fatalf("got %s, want %s", got, fmt.Sprint(wantFields))
+	}
+
+	if !strings.Contains(entry.ResponseExample, "event: done") {
+		t.Fatalf("expect whole stream to be recorded: %q", entry.ResponseExample)
+	}
+}
+
+func TestParseEvents_NDJSON(t *testing.T) {
+	startedAt := time.Now()
+	body := []byte("{\"step\":1}\n\n{\"step\":2}\n")
+	writes := []write{
+		{end: 11, at: startedAt.Add(1 * time.Millisecond)},
+		{end: 23, at: startedAt.Add(5 * time.Millisecond)},
+	}
+
+	got := parseEvents("application/x-ndjson; charset=utf-8", body, writes, startedAt)
+	want := []Event{
+		{Index: 0, Data: `{"step":1}`, Offset: 1 * time.Millisecond},
+		{Index: 1, Data: `{"step":2}`, Offset: 5 * time.Millisecond},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if got := parseEvents("application/json", body, writes, startedAt); got != nil {
+		t.Fatalf("expect non streaming response not to be parsed: %v", got)
+	}
+}
+
+func TestValidator_ResponseEvent_NotFound(t *testing.T) {
+	var buf bytes.Buffer
+	tFatalf = fprintFatalFunc(&buf)
+
+	validator := newValidator()
+	validator.record.responseEvents = []Event{{Index: 0, Name: "progress", Data: `{"step":1}`}}
+
+	validator.ResponseEvent(t, 1, []TestCase{}, &Progress{})
+	if got, want := buf.String(), "response event 1 is not found"; !strings.Contains(got, want) {
+		t.Fatalf("expect %q to contain %q", got, want)
+	}
+
+	buf.Reset()
+	validator.ResponseEventByName(t, "done", []TestCase{}, &Progress{})
+	if got, want := buf.String(), `response event "done" is not found`; !strings.Contains(got, want) {
+		t.Fatalf("expect %q to contain %q", got, want)
+	}
+}
+
+func TestDocument_Generate_ResponseEvents(t *testing.T) {
+	doc := &Document{
+		Entries: []Entry{
+			{
+				Method:             "GET",
+				Path:               "/v1/jobs/1/events",
+				ResponseStatusCode: 200,
+				ResponseEvents: []Event{
+					{Index: 0, ID: "1", Name: "progress", Data: "{\"step\": 1,\n\"state\": \"running\"}", Offset: time.Millisecond},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := doc.generate(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	want := "| 0 | progress | 1 | `{\"step\": 1, \"state\": \"running\"}` |"
+	if !strings.Contains(buf.String(), want) {
+		t.Fatalf("expect %q to contain %q", buf.String(), want)
+	}
+
+	// Offsets change on every run, so they are not written.
+	if strings.Contains(buf.String(), "1ms") {
+		t.Fatalf("expect %q not to contain offset", buf.String())
+	}
+}
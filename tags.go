@@ -0,0 +1,52 @@
+package httpdoc
+
+// untaggedGroup is the tag name of group of entries which don't have any tags.
+const untaggedGroup = "Others"
+
+// TagGroup is group of entries which have the same tag. Normally, you don't need to modify this.
+// All fields are exported just for templating.
+type TagGroup struct {
+	// Tag is tag name.
+	Tag string
+
+	// Entries is entries which have the tag.
+	Entries []Entry
+}
+
+// TagGroups groups entries by RecordOption.Tags. Entry which has multiple tags belongs to all of
+// the groups. Groups are sorted by the order of first appearance and entries without tags are grouped
+// into the last group `Others`. If no entry has tags, it returns nil.
+func (d *Document) TagGroups() []TagGroup {
+	return tagGroups(d.Entries)
+}
+
+func tagGroups(entries []Entry) []TagGroup {
+	var (
+		groups   []TagGroup
+		untagged []Entry
+	)
+	index := make(map[string]int)
+	for _, e := range entries {
+		if len(e.Tags) == 0 {
+			untagged = append(untagged, e)
+			continue
+		}
+		for _, tag := range e.Tags {
+			i, ok := index[tag]
+			if !ok {
+				i = len(groups)
+				index[tag] = i
+				groups = append(groups, TagGroup{Tag: tag})
+			}
+			groups[i].Entries = append(groups[i].Entries, e)
+		}
+	}
+
+	if len(groups) == 0 {
+		return nil
+	}
+	if len(untagged) > 0 {
+		groups = append(groups, TagGroup{Tag: untaggedGroup, Entries: untagged})
+	}
+	return groups
+}
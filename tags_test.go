@@ -0,0 +1,76 @@
+package httpdoc
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocument_TagGroups(t *testing.T) {
+	doc := &Document{
+		Entries: []Entry{
+			{Method: "GET", Path: "/v1/user", Tags: []string{"user"}},
+			{Method: "GET", Path: "/v1/health"},
+			{Method: "GET", Path: "/v1/user/items", Tags: []string{"item", "user"}},
+		},
+	}
+
+	var got [][]string
+	for _, g := range doc.TagGroups() {
+		group := []string{g.Tag}
+		for _, e := range g.Entries {
+			group = append(group, e.Path)
+		}
+		got = append(got, group)
+	}
+
+	want := [][]string{
+		{"user", "/v1/user", "/v1/user/items"},
+		{"item", "/v1/user/items"},
+		{"Others", "/v1/health"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if got := (&Document{Entries: []Entry{{Path: "/v1/health"}}}).TagGroups(); got != nil {
+		t.Fatalf("expect nil if no entry has tags: %v", got)
+	}
+}
+
+func TestDocument_Generate_Metadata(t *testing.T) {
+	doc := &Document{
+		Entries: []Entry{
+			{
+				Method:             "GET",
+				Path:               "/v1/user",
+				ResponseStatusCode: 200,
+				Summary:            "Get user",
+				Tags:               []string{"user"},
+				OperationID:        "getUser",
+				Deprecated:         true,
+				Replacement:        "GET /v2/user",
+				Since:              "v1.0.0",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := doc.generate(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, want := range []string{
+		"### user\n\n- /v1/user\n  - [[200] GET /v1/user](#200-get-v1user) - Get user (deprecated)",
+		"> **Deprecated**: use `GET /v2/user` instead.",
+		"**Get user**",
+		"Operation ID: `getUser`",
+		"Tags: `user`",
+		"Since: `v1.0.0`",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("expect %q to contain %q", buf.String(), want)
+		}
+	}
+}
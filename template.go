@@ -1,11 +1,15 @@
 package httpdoc
 
 import (
+	"bytes"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
+	"unicode"
 
 	"go.mercari.io/go-httpdoc/static"
 )
@@ -36,36 +40,226 @@ func (d *Document) Generate(path string) error {
 	return d.generate(f)
 }
 
+// GenerateDir writes documentation into the given directory. It writes `index.md` which has table of
+// contents and one file per endpoint (method and path), e.g., `post-v1-user.md`. If Document.SplitByTag
+// is true, it writes one file per tag instead. If file names collide, suffixes are added (e.g.,
+// `get-v1-user-info-1.md`). Pages generated by the previous run which are not generated anymore are
+// removed. Generation is skipped if EnvHTTPDoc is empty. If directory does not exist, it creates it.
+func (d *Document) GenerateDir(dir string) error {
+
+	// Only generate documentation when EnvHttpDoc has non-empty value
+	if os.Getenv(EnvHTTPDoc) == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	return d.generateDir(dir)
+}
+
+// page is a file of documentation generated by GenerateDir.
+type page struct {
+	File    string
+	Title   string
+	Entries []Entry
+}
+
+func (d *Document) generateDir(dir string) error {
+	tmpl, err := d.parseTemplate()
+	if err != nil {
+		return err
+	}
+
+	view := d.view()
+	pages, href := view.pages()
+	tmpl.Funcs(template.FuncMap{"href": href})
+
+	write := func(name, tmplName string, data interface{}) error {
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return tmpl.ExecuteTemplate(f, tmplName, data)
+	}
+
+	if err := write("index.md", "index", view); err != nil {
+		return err
+	}
+	written := make(map[string]bool)
+	for _, p := range pages {
+		if err := write(p.File, "page", p); err != nil {
+			return err
+		}
+		written[p.File] = true
+	}
+	return removeStalePages(dir, written)
+}
+
+// pageMarker is written in every page generated by GenerateDir. It's used to find stale pages.
+const pageMarker = "[Back to index](index.md)"
+
+// removeStalePages removes pages which are generated by the previous run but not written this time.
+// Files which are not generated by GenerateDir are kept.
+func removeStalePages(dir string, written map[string]bool) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		name := f.Name()
+		if f.IsDir() || filepath.Ext(name) != ".md" || name == "index.md" || written[name] {
+			continue
+		}
+		buf, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		if !bytes.Contains(buf, []byte(pageMarker)) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pages splits entries into pages. It also returns the function which returns the link to the entry
+// from index. Since an entry which has multiple tags is written in all of the pages, the page of its
+// first tag is linked.
+func (v *documentView) pages() ([]page, func(e Entry) string) {
+	var (
+		pages   []page
+		titleOf func(e Entry) string
+	)
+	if groups := v.TagGroups(); v.SplitByTag && groups != nil {
+		for _, g := range groups {
+			pages = append(pages, page{Title: g.Tag, Entries: g.Entries})
+		}
+		titleOf = func(e Entry) string {
+			if len(e.Tags) == 0 {
+				return untaggedGroup
+			}
+			return e.Tags[0]
+		}
+	} else {
+		for _, group := range groupEntries(v.Entries) {
+			pages = append(pages, page{Title: group[0].endpoint(), Entries: group})
+		}
+		titleOf = func(e Entry) string {
+			return e.endpoint()
+		}
+	}
+
+	// Different titles can have the same file name (e.g., `/v1/user-info` and `/v1/user/info`),
+	// so suffixes are added like anchors.
+	used := map[string]bool{"index": true}
+	fileOf := make(map[string]string)
+	for i := range pages {
+		slug := pageSlug(pages[i].Title)
+		name := slug
+		for n := 1; used[name]; n++ {
+			name = fmt.Sprintf("%s-%d", slug, n)
+		}
+		used[name] = true
+		pages[i].File = name + ".md"
+		fileOf[pages[i].Title] = pages[i].File
+	}
+
+	// Anchors are unique in each page, so links are resolved after anchors of pages are set.
+	links := make(map[int]string)
+	for _, p := range pages {
+		setAnchors(p.Entries)
+		for _, e := range p.Entries {
+			if _, ok := links[e.id]; !ok && fileOf[titleOf(e)] == p.File {
+				links[e.id] = p.File + "#" + e.Anchor
+			}
+		}
+	}
+	return pages, func(e Entry) string {
+		return links[e.id]
+	}
+}
+
+// pageSlug returns stable file name without extension for the page title, e.g., `post-v1-user` for
+// `POST /v1/user`. Letters (including non-ASCII letters) and numbers are kept and others are replaced
+// with hyphens.
+func pageSlug(title string) string {
+	var buf bytes.Buffer
+	hyphen := false
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r) {
+			if hyphen && buf.Len() > 0 {
+				buf.WriteByte('-')
+			}
+			buf.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		hyphen = true
+	}
+	if buf.Len() == 0 {
+		return "page"
+	}
+	return buf.String()
+}
+
 func (d *Document) generate(w io.Writer) error {
+	tmpl, err := d.parseTemplate()
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, d.view())
+}
+
+func (d *Document) parseTemplate() (*template.Template, error) {
 	if d.tmpl == "" {
 		d.tmpl = defaultTmpl
 	}
 
 	buf, err := static.Asset(d.tmpl)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return d.tmplExecute(w, string(buf))
+	return template.New("httpdoc").Funcs(d.funcMap()).Parse(string(buf))
 }
 
 func (d *Document) tmplExecute(w io.Writer, text string) error {
-	tmpl, err := template.New("httpdoc").Funcs(funcMap()).Parse(text)
+	tmpl, err := template.New("httpdoc").Funcs(d.funcMap()).Parse(text)
 	if err != nil {
 		return err
 	}
 
-	if err := tmpl.Execute(w, d); err != nil {
+	if err := tmpl.Execute(w, d.view()); err != nil {
 		return err
 	}
 	return nil
 }
 
-func funcMap() template.FuncMap {
+func (d *Document) funcMap() template.FuncMap {
 	return template.FuncMap{
 		"lower": strings.ToLower,
 		"stripslash": func(s string) string {
 			return strings.Replace(s, "/", "", -1)
 		},
+		"oneline": func(s string) string {
+			return strings.Replace(s, "\n", " ", -1)
+		},
+		// href returns the link to the entry from table of contents.
+		"href": func(e Entry) string {
+			return "#" + e.Anchor
+		},
+		"toctree":    tocTree,
+		"fieldtable": fieldTable,
+		"join": func(s []string) string {
+			return strings.Join(s, ", ")
+		},
+		"curl":   d.curl,
+		"httpie": d.httpie,
+		"gohttp": d.goHTTP,
 	}
 }
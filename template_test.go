@@ -1,8 +1,12 @@
 package httpdoc
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -67,8 +71,56 @@ func TestDocument_Generate_noEnv(t *testing.T) {
 	}
 }
 
+func TestDocument_Generate_OptionalSections(t *testing.T) {
+	doc := &Document{
+		SecuritySchemes: []SecurityScheme{
+			{Name: "oauth", Type: SecurityOAuth2, Scopes: map[string]string{"read": "Read user"}},
+		},
+		Entries: []Entry{
+			{
+				Method:             "GET",
+				Path:               "/v1/events",
+				ResponseStatusCode: 200,
+				Tags:               []string{"event"},
+				ResponseEvents:     []Event{{Index: 1, Name: "update", Data: "{}"}},
+				ResponseExample:    "data: {}",
+			},
+			{
+				Method:             "GET",
+				Path:               "/v1/ws",
+				ResponseStatusCode: 101,
+				Tags:               []string{"ws"},
+				WebSocket:          &WebSocketSession{Messages: []WebSocketMessage{{Index: 1, Direction: "received", Type: "text", Payload: "{}"}}},
+				ResponseExample:    "{}",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := doc.generate(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	got := buf.String()
+
+	// Sections which are not rendered must not leave blank lines.
+	for _, want := range []string{
+		"  - [[200] GET /v1/events](#200-get-v1events)\n\n### ws\n",
+		"| read | Read user |\n\n\n## [200] GET /v1/events\n",
+		"| 1 | update |  | `{}` |\n\nResponse example\n",
+		"### Request\n\n\n\n\n\n\n\n\n\n### Response\n",
+		"{}\n```\n\nResponse example\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expect %q to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "\n\n\n\n## ") {
+		t.Fatalf("expect no extra blank lines before sections: %q", got)
+	}
+}
+
 func TestFuncMap(t *testing.T) {
-	m := funcMap()
+	m := (&Document{}).funcMap()
 	lower := m["lower"].(func(s string) string)
 	if got, want := lower("DOC"), "doc"; got != want {
 		t.Fatalf("got %q, want %q", got, want)
@@ -78,6 +130,12 @@ func TestFuncMap(t *testing.T) {
 	if got, want := stripslash("/v2/user/contact"), "v2usercontact"; got != want {
 		t.Fatalf("got %q, want %q", got, want)
 	}
+
+	for _, name := range []string{"curl", "httpie", "gohttp"} {
+		if _, ok := m[name].(func(Entry) string); !ok {
+			t.Fatalf("expect %q to be defined", name)
+		}
+	}
 }
 
 func TestTemplateGenerate_NotExistDir(t *testing.T) {
@@ -119,3 +177,148 @@ func TestTmplExecute_InvalidTemplate(t *testing.T) {
 		}
 	}
 }
+
+func TestDocument_GenerateDir(t *testing.T) {
+	resetF := setEnv(t, EnvHTTPDoc, "1")
+	defer resetF()
+
+	entries := []Entry{
+		{Method: "POST", Path: "/v1/user", ResponseStatusCode: 200, Tags: []string{"user"}},
+		{Method: "POST", Path: "/v1/user", ResponseStatusCode: 400, Tags: []string{"user"}},
+		{Method: "GET", Path: "/v1/health", ResponseStatusCode: 200},
+	}
+
+	cases := []struct {
+		splitByTag bool
+		wantFiles  []string
+		wantLink   string
+	}{
+		{false, []string{"index.md", "post-v1-user.md", "get-v1-health.md"}, "](post-v1-user.md#400-post-v1user)"},
+		{true, []string{"index.md", "user.md", "others.md"}, "](others.md#200-get-v1health)"},
+	}
+
+	for i, tc := range cases {
+		dir, err := ioutil.TempDir("", "httpdoc")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		doc := &Document{Entries: entries, SplitByTag: tc.splitByTag}
+		if err := doc.GenerateDir(filepath.Join(dir, "doc")); err != nil {
+			t.Fatalf("#%d: err: %s", i, err)
+		}
+
+		files, err := ioutil.ReadDir(filepath.Join(dir, "doc"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := len(files), len(tc.wantFiles); got != want {
+			t.Fatalf("#%d: got %d files, want %d", i, got, want)
+		}
+		for _, name := range tc.wantFiles {
+			if _, err := os.Stat(filepath.Join(dir, "doc", name)); err != nil {
+				t.Fatalf("#%d: expect %s to be generated: %s", i, name, err)
+			}
+		}
+
+		index, _ := ioutil.ReadFile(filepath.Join(dir, "doc", "index.md"))
+		if !strings.Contains(string(index), tc.wantLink) {
+			t.Fatalf("#%d: expect %q to contain %q", i, index, tc.wantLink)
+		}
+		if strings.Contains(string(index), "### Request") {
+			t.Fatalf("#%d: expect index not to contain entries: %q", i, index)
+		}
+
+		page, _ := ioutil.ReadFile(filepath.Join(dir, "doc", tc.wantFiles[1]))
+		for _, want := range []string{"[Back to index](index.md)", "## [200] POST /v1/user", "## [400] POST /v1/user"} {
+			if !strings.Contains(string(page), want) {
+				t.Fatalf("#%d: expect %q to contain %q", i, page, want)
+			}
+		}
+	}
+}
+
+func TestDocument_GenerateDir_Collision(t *testing.T) {
+	resetF := setEnv(t, EnvHTTPDoc, "1")
+	defer resetF()
+
+	dir, err := ioutil.TempDir("", "httpdoc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Pages of the previous run which are not generated anymore are removed,
+	// but other files are kept.
+	stale := "# Stale\n\n[Back to index](index.md)\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "get-v1-stale.md"), []byte(stale), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "README.md"), []byte("# README\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := &Document{
+		Entries: []Entry{
+			{Method: "GET", Path: "/v1/user-info", Tags: []string{"User"}},
+			{Method: "GET", Path: "/v1/user/info", Tags: []string{"user"}},
+			{Method: "GET", Path: "/v1/items", Tags: []string{"商品"}},
+		},
+	}
+	if err := doc.GenerateDir(dir); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range files {
+		got = append(got, f.Name())
+	}
+	want := []string{"README.md", "get-v1-items.md", "get-v1-user-info-1.md", "get-v1-user-info.md", "index.md"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	index, _ := ioutil.ReadFile(filepath.Join(dir, "index.md"))
+	for _, want := range []string{"](get-v1-user-info.md#", "](get-v1-user-info-1.md#"} {
+		if !strings.Contains(string(index), want) {
+			t.Fatalf("expect %q to contain %q", index, want)
+		}
+	}
+
+	// Tags which differ only in case and non-ASCII tags have their own pages.
+	doc.SplitByTag = true
+	if err := doc.GenerateDir(dir); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, name := range []string{"user.md", "user-1.md", "商品.md"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("expect %s to be generated: %s", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "get-v1-items.md")); !os.IsNotExist(err) {
+		t.Fatalf("expect get-v1-items.md to be removed: %v", err)
+	}
+}
+
+func TestPageSlug(t *testing.T) {
+	cases := []struct {
+		title string
+		want  string
+	}{
+		{"GET /v1/user/{id}/items", "get-v1-user-id-items"},
+		{"User Info", "user-info"},
+		{"ユーザー 管理", "ユーザー-管理"},
+		{"/", "page"},
+	}
+
+	for _, tc := range cases {
+		if got := pageSlug(tc.title); got != tc.want {
+			t.Fatalf("got %q, want %q", got, tc.want)
+		}
+	}
+}
@@ -7,15 +7,22 @@ import (
 	"net/url"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
-	"github.com/tenntenn/gpath"
+	protov2 "google.golang.org/protobuf/proto"
 )
 
 var (
 	defaultUnmarshalFunc = json.Unmarshal
 
 	defaultAssertFunc = func(t *testing.T, expected, actual interface{}, desc string) {
+		if m, ok := expected.(Matcher); ok {
+			if !m.Match(actual) {
+				tFatalf(t, "%s: got %#v(%T), want %s", desc, actual, actual, m)
+			}
+			return
+		}
 		if !reflect.DeepEqual(expected, actual) {
 			tFatalf(t, "%s: got %#v(%T), want %#v(%T)", desc, actual, actual, expected, expected)
 		}
@@ -26,18 +33,25 @@ var (
 	}
 
 	protoUnmarshalFunc = func(data []byte, v interface{}) error {
-		unmashaler, ok := v.(proto.Unmarshaler)
-		if !ok {
-			return fmt.Errorf("failed to type assert to Unmashaler: %T must implement proto.Unmarshaler interface", v)
+		switch m := v.(type) {
+		case proto.Unmarshaler:
+			return m.Unmarshal(data)
+		case protov2.Message:
+			return protov2.Unmarshal(data, m)
+		case proto.Message:
+			return proto.Unmarshal(data, m)
 		}
-		return unmashaler.Unmarshal(data)
+		return fmt.Errorf("failed to type assert to Unmashaler: %T must implement proto.Unmarshaler or proto.Message interface", v)
 	}
 )
 
 var tFatalf fatalFunc = defaultFatalFunc
 
+// AssertFunc asserts the actual value with the expected value in TestCase. It should fail the test
+// with the description when they are not matched.
+type AssertFunc func(t *testing.T, expected, actual interface{}, desc string)
+
 type (
-	assertFunc    func(t *testing.T, expected, actual interface{}, desc string)
 	fatalFunc     func(t *testing.T, format string, args ...interface{})
 	unmarshalFunc func(data []byte, v interface{}) error
 )
@@ -47,9 +61,22 @@ type (
 type Validator struct {
 	record *record
 
-	unmarshalFunc unmarshalFunc
-	assertFunc    assertFunc
+	// operationID is RecordOption.OperationID.
+	operationID string
+
+	requestUnmarshalFunc  unmarshalFunc
+	responseUnmarshalFunc unmarshalFunc
+	assertFunc            AssertFunc
+
+	// requestMessage and responseMessage create new messages of ProtoBufferOption message types.
+	// They are used when body is validated without struct.
+	requestMessage  func() protov2.Message
+	responseMessage func() protov2.Message
+
+	// jsonRPC is the JSON-RPC call which is validated. See RecordOption.JSONRPC.
+	jsonRPC *JSONRPCCall
 
+	pathParams     []Data
 	requestParams  []Data
 	requestHeaders []Data
 	requestFields  []Data
@@ -59,6 +86,11 @@ type Validator struct {
 }
 
 type record struct {
+	requestMethod  string
+	requestURL     string
+	requestProto   string
+	requestPath    string
+	pathParams     []Data
 	requestParams  url.Values
 	requestHeaders http.Header
 	requestBody    []byte
@@ -66,12 +98,22 @@ type record struct {
 	responseStatusCode int
 	responseHeaders    http.Header
 	responseBody       []byte
+	responseEvents     []Event
+
+	startedAt time.Time
 }
 
 // TestCase is test case validator uses. Validator inspects and extract request & response value based on
 // Target (e.g, when testing request params, target is parameter name. when testing response
 // body, target is filed name) and asserts with Expected value.
 //
+// When testing body, target is a path of fields separated by `.` and can contain slice indices
+// (`Items[0].Name`), map keys (`Preference["email"]`) and wildcards (`Items[*].Name`). With
+// wildcards, every element is asserted with Expected value.
+//
+// Expected can be a Matcher (e.g., IsUUID()) when the value changes on every request. Then the
+// description of the matcher is written in documentation instead of the value.
+//
 // TestCase can be used like table-driven way.
 //
 //   validator.RequestParams(t, []httpdoc.TestCase{
@@ -83,7 +125,12 @@ type TestCase struct {
 	Target      string
 	Expected    interface{}
 	Description string
-	AssertFunc  assertFunc
+	AssertFunc  AssertFunc
+
+	// Schema describes the body field explicitly. Non-empty fields override the schema populated
+	// from the struct. Required can only make the field required, the field populated as required
+	// is kept required. See Schema.
+	Schema *Schema
 }
 
 // NewTestCase returns new TestCase.
@@ -93,9 +140,10 @@ func NewTestCase(target string, expected interface{}, description string) TestCa
 
 func newValidator() *Validator {
 	return &Validator{
-		unmarshalFunc: defaultUnmarshalFunc,
-		assertFunc:    defaultAssertFunc,
-		record:        &record{},
+		requestUnmarshalFunc:  defaultUnmarshalFunc,
+		responseUnmarshalFunc: defaultUnmarshalFunc,
+		assertFunc:            defaultAssertFunc,
+		record:                &record{},
 	}
 }
 
@@ -177,9 +225,16 @@ func (v *Validator) ResponseHeaders(t *testing.T, cases []TestCase) {
 //       Email string
 //   }
 //
+// If request is nil, new message of ProtoBufferOption.RequestMessage or ProtoBufferOption.RequestType
+// is used. Without message types, request body is unmarshaled into generic json value and
+// TestCase.Target is json field names (e.g., `variables.id`).
 func (v *Validator) RequestBody(t *testing.T, cases []TestCase, request interface{}) {
+	if request == nil {
+		request = v.newBody(v.requestMessage)
+	}
+
 	// Unmarshal request body into the given struct
-	if err := v.unmarshalFunc(v.record.requestBody, request); err != nil {
+	if err := v.requestUnmarshalFunc(v.record.requestBody, request); err != nil {
 		tFatalf(t, "Failed to unmarshal request body: %s", err)
 		return
 	}
@@ -199,14 +254,31 @@ func (v *Validator) RequestBody(t *testing.T, cases []TestCase, request interfac
 //       Email string
 //   }
 //
+// If response is nil, new message of ProtoBufferOption.ResponseMessage or ProtoBufferOption.ResponseType
+// is used. Without message types, response body is unmarshaled into generic json value and
+// TestCase.Target is json field names (e.g., `data.user.name`).
 func (v *Validator) ResponseBody(t *testing.T, cases []TestCase, response interface{}) {
+	if response == nil {
+		response = v.newBody(v.responseMessage)
+	}
+
 	// Unmarshal request body into the given struct
-	if err := v.unmarshalFunc(v.record.responseBody, response); err != nil {
+	if err := v.responseUnmarshalFunc(v.record.responseBody, response); err != nil {
 		tFatalf(t, "Failed to unmarshal response body: %s", err)
 	}
 	v.validateFields(t, cases, response, &v.responseFields)
 }
 
+// newBody returns new value which body is unmarshaled into. It's new message if the message type is
+// given, otherwise generic json value.
+func (v *Validator) newBody(message func() protov2.Message) interface{} {
+	if message != nil {
+		return message()
+	}
+	var body interface{}
+	return &body
+}
+
 func (vl *Validator) validateFields(t *testing.T, cases []TestCase, v interface{}, fields *[]Data) {
 	for _, tc := range cases {
 		data := Data{
@@ -214,13 +286,31 @@ func (vl *Validator) validateFields(t *testing.T, cases []TestCase, v interface{
 			Value:       tc.Expected,
 			Description: tc.Description,
 		}
+
+		path, err := parseFieldPath(tc.Target)
+		if err != nil {
+			*fields = append(*fields, data)
+			tFatalf(t, "%s", err)
+			continue
+		}
+
+		values := path.values(v)
+		var actual interface{}
+		if len(values) > 0 {
+			actual = values[0]
+		}
+		data.Schema = path.schema(v, actual).merge(tc.Schema)
+		data.explicit = tc.Schema
 		*fields = append(*fields, data)
-		actual, _ := gpath.At(v, tc.Target)
-		pickAssertFunc(&tc, vl)(t, tc.Expected, actual, tc.Description)
+
+		// With wildcards, every matched element must be the expected value.
+		for _, actual := range values {
+			pickAssertFunc(&tc, vl)(t, tc.Expected, actual, tc.Description)
+		}
 	}
 }
 
-func pickAssertFunc(tc *TestCase, v *Validator) assertFunc {
+func pickAssertFunc(tc *TestCase, v *Validator) AssertFunc {
 	if tc.AssertFunc != nil {
 		return tc.AssertFunc
 	}
@@ -33,8 +33,8 @@ type SNS struct {
 	Facebook string `json:"facebook"`
 }
 
-// testAssertWithCount returns assertFunc it counts failed test instead of fail.
-func testAssertWithCount(fails *int) assertFunc {
+// testAssertWithCount returns AssertFunc it counts failed test instead of fail.
+func testAssertWithCount(fails *int) AssertFunc {
 	return func(t *testing.T, expected, actual interface{}, desc string) {
 		if !reflect.DeepEqual(expected, actual) {
 			*fails++
@@ -77,7 +77,7 @@ func TestValidator_RequestParams(t *testing.T) {
 	validator.RequestParams(t, []TestCase{
 		NewTestCase("token", "12345", ""),
 		NewTestCase("pretty", "true", ""),
-		{"year", "thisyear", "", func(t *testing.T, expected, actual interface{}, desc string) {
+		{Target: "year", Expected: "thisyear", AssertFunc: func(t *testing.T, expected, actual interface{}, desc string) {
 			if expected != "thisyear" {
 				t.Fatal("expected is not thisyear")
 			}
@@ -156,7 +156,7 @@ func TestValidator_ResponseHeaders(t *testing.T) {
 	validator.ResponseHeaders(t, []TestCase{
 		NewTestCase("Content-Type", "application/json", ""),
 		NewTestCase("X-API-Version", "1.1.2", ""),
-		{"Content-Length", []string{"content length"}, "length is change every time", func(t *testing.T, expected, actual interface{}, desc string) {
+		{Target: "Content-Length", Expected: []string{"content length"}, Description: "length is change every time", AssertFunc: func(t *testing.T, expected, actual interface{}, desc string) {
 			contentLength, err := strconv.Atoi(actual.(string))
 			if err != nil {
 				t.Fatal("actual is not number")
@@ -245,14 +245,14 @@ func TestValidator_ResponseBody(t *testing.T) {
 		NewTestCase("ID", 789, ""),
 		NewTestCase("Active", false, ""),
 		NewTestCase("Setting.Email", "tcnksm@mercari.com", ""),
-		{"Setting.Email", "custommail", "", func(t *testing.T, expected, actual interface{}, desc string) {
+		{Target: "Setting.Email", Expected: "custommail", AssertFunc: func(t *testing.T, expected, actual interface{}, desc string) {
 			if expected != "custommail" {
 				t.Fatal("Setting.Email is not custommail")
 			}
 			custommailCalledAssertFunc = true
 		}},
 		NewTestCase("Permission[1]", "read", ""),
-		{`Preference["email"]`, 0, "", nil},
+		{Target: `Preference["email"]`, Expected: 0},
 	}, &User{})
 
 	if custommailCalledAssertFunc == false {
@@ -266,7 +266,7 @@ func TestValidator_ResponseBody(t *testing.T) {
 		NewTestCase("Active", true, ""),
 		NewTestCase("Setting.Email", "deeeet@gmail.com", ""),
 		NewTestCase("Permission[1]", "write", ""),
-		{`Preference["email"]`, 1, "", nil},
+		{Target: `Preference["email"]`, Expected: 1},
 	}, &User{})
 
 	if want := 5; got != want {
@@ -305,7 +305,7 @@ func TestValidateFields(t *testing.T) {
 		NewTestCase("ID", 12345, ""),
 		NewTestCase("Name", "tcnksm", ""),
 		NewTestCase("Active", true, ""),
-		{"Active", "customactive", "", func(t *testing.T, expected, actual interface{}, desc string) {
+		{Target: "Active", Expected: "customactive", AssertFunc: func(t *testing.T, expected, actual interface{}, desc string) {
 			if expected != "customactive" {
 				t.Fatal("Acitve is not customactive")
 			}
@@ -314,7 +314,7 @@ func TestValidateFields(t *testing.T) {
 		NewTestCase("Setting.Email", "tcnksm@example.com", ""),
 		NewTestCase("Setting.SNS.Twitter", "@deeeet", ""),
 		NewTestCase("Permission[0]", "write", ""),
-		{`Preference["email"]`, 0, "", nil},
+		{Target: `Preference["email"]`, Expected: 0},
 	}, testUser, &[]Data{})
 
 	if activeCalledAssertFunc == false {
@@ -333,12 +333,13 @@ func TestValidator_RequestBody_Proto(t *testing.T) {
 
 	validator := newValidator()
 	validator.record.requestBody = buf
-	validator.unmarshalFunc = protoUnmarshalFunc
+	validator.requestUnmarshalFunc = protoUnmarshalFunc
+	validator.responseUnmarshalFunc = protoUnmarshalFunc
 	customIDCalledAssertFunc := false
 	validator.RequestBody(t, []TestCase{
 		NewTestCase("Id", int32(12345), ""),
 		NewTestCase("Name", "tcnksm", ""),
-		{"Id", "customid", "custom assert func test", func(t *testing.T, expected, actual interface{}, desc string) {
+		{Target: "Id", Expected: "customid", Description: "custom assert func test", AssertFunc: func(t *testing.T, expected, actual interface{}, desc string) {
 			if expected != "customid" {
 				t.Fatal("expected is not customid")
 			}
@@ -373,7 +374,8 @@ func TestValidator_ResponseBody_Proto(t *testing.T) {
 	}
 
 	validator := newValidator()
-	validator.unmarshalFunc = protoUnmarshalFunc
+	validator.requestUnmarshalFunc = protoUnmarshalFunc
+	validator.responseUnmarshalFunc = protoUnmarshalFunc
 	validator.record.responseBody = buf
 	validator.ResponseBody(t, []TestCase{
 		NewTestCase("Id", int32(667854), ""),
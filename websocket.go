@@ -0,0 +1,315 @@
+package httpdoc
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// WebSocketClientToServer is direction of message which client sends to server.
+	WebSocketClientToServer = "client->server"
+
+	// WebSocketServerToClient is direction of message which server sends to client.
+	WebSocketServerToClient = "server->client"
+)
+
+// WebSocket opcodes. See https://tools.ietf.org/html/rfc6455#section-5.2
+const (
+	webSocketContinuation = 0x0
+	webSocketText         = 0x1
+	webSocketBinary       = 0x2
+	webSocketClose        = 0x8
+	webSocketPing         = 0x9
+	webSocketPong         = 0xa
+)
+
+var webSocketOpcodeNames = map[int]string{
+	webSocketText:   "text",
+	webSocketBinary: "binary",
+	webSocketClose:  "close",
+	webSocketPing:   "ping",
+	webSocketPong:   "pong",
+}
+
+// WebSocketSession is a recorded WebSocket session, i.e., messages exchanged after the upgrade handshake
+// until handler returns. Normally, you don't need to modify this. All fields are exported just for templating.
+type WebSocketSession struct {
+	// Subprotocol is the subprotocol which server selected in the handshake (`Sec-WebSocket-Protocol`).
+	Subprotocol string
+
+	// Messages is messages exchanged in the session in the order they are sent.
+	Messages []WebSocketMessage
+}
+
+// WebSocketMessage is a message of WebSocket session. Fragmented frames are merged into one message.
+// Normally, you don't need to modify this. All fields are exported just for templating.
+type WebSocketMessage struct {
+	// Index is the position of the message in the session (0-origin).
+	Index int
+
+	// Direction is WebSocketClientToServer or WebSocketServerToClient.
+	Direction string
+
+	// Opcode is WebSocket opcode of the message (e.g., 1 for text, 2 for binary).
+	Opcode int
+
+	// Type is human readable name of Opcode (e.g., `text`, `binary` or `close`).
+	Type string
+
+	// Payload is human readable message payload. JSON text is indented. Binary payload is unmarshaled
+	// by ProtoBufferOption (request message for messages from client, response message for messages
+	// from server) and encoded into json format if provided, otherwise it's base64 encoded.
+	// Close payload is shown as status code and reason.
+	Payload string
+
+	// Offset is time from handler is called until the message is sent. It's used to order messages
+	// and is not written in documentation because it changes on every run.
+	Offset time.Duration
+
+	// data is raw (unmasked and decompressed) payload.
+	data []byte
+}
+
+type byOffset []WebSocketMessage
+
+func (m byOffset) Len() int           { return len(m) }
+func (m byOffset) Less(i, j int) bool { return m[i].Offset < m[j].Offset }
+func (m byOffset) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+
+// Hijack implements http.Hijacker to allow handlers to upgrade connection to WebSocket.
+// The returned connection records bytes which are exchanged on it.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("httpdoc: http.ResponseWriter does not implement http.Hijacker")
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	w.hijacked = &hijackedConn{Conn: conn}
+
+	// Bytes which server already read from client are kept in the original reader.
+	var r io.Reader = w.hijacked
+	if n := rw.Reader.Buffered(); n > 0 {
+		buffered, _ := rw.Reader.Peek(n)
+		w.hijacked.record(&w.hijacked.received, &w.hijacked.receivedWrites, buffered)
+		r = io.MultiReader(bytes.NewReader(append([]byte(nil), buffered...)), w.hijacked)
+	}
+
+	return w.hijacked, bufio.NewReadWriter(bufio.NewReader(r), bufio.NewWriter(w.hijacked)), nil
+}
+
+// hijackedConn is a net.Conn which records bytes received from client and sent to client.
+type hijackedConn struct {
+	net.Conn
+
+	mu             sync.Mutex
+	received       []byte
+	receivedWrites []write
+	sent           []byte
+	sentWrites     []write
+}
+
+func (c *hijackedConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	c.record(&c.received, &c.receivedWrites, b[:n])
+	return n, err
+}
+
+func (c *hijackedConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.record(&c.sent, &c.sentWrites, b[:n])
+	return n, err
+}
+
+func (c *hijackedConn) record(buf *[]byte, writes *[]write, b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	*buf = append(*buf, b...)
+	*writes = append(*writes, write{end: len(*buf), at: time.Now()})
+}
+
+// webSocketSession parses recorded bytes as WebSocket handshake and frames. If server did not respond
+// with `101 Switching Protocols`, it returns nil.
+func (c *hijackedConn) webSocketSession(r *http.Request, startedAt time.Time, requestMessage, responseMessage protoMessage) (*http.Response, *WebSocketSession) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Handshake response is written on the hijacked connection, so parse it first.
+	end := bytes.Index(c.sent, []byte("\r\n\r\n"))
+	if end < 0 {
+		return nil, nil
+	}
+	end += 4
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(c.sent[:end])), r)
+	if err != nil || res.StatusCode != http.StatusSwitchingProtocols {
+		return nil, nil
+	}
+
+	messages := parseWebSocketMessages(WebSocketClientToServer, c.received, 0, c.receivedWrites, startedAt)
+	messages = append(messages, parseWebSocketMessages(WebSocketServerToClient, c.sent, end, c.sentWrites, startedAt)...)
+	sort.Stable(byOffset(messages))
+	for i := range messages {
+		messages[i].Index = i
+
+		message := responseMessage
+		if messages[i].Direction == WebSocketClientToServer {
+			message = requestMessage
+		}
+		messages[i].Payload = webSocketPayload(&messages[i], message)
+	}
+
+	return res, &WebSocketSession{
+		Subprotocol: res.Header.Get("Sec-WebSocket-Protocol"),
+		Messages:    messages,
+	}
+}
+
+// parseWebSocketMessages parses WebSocket frames from data[start:] and merges fragmented frames into
+// messages. Truncated frame at the end is ignored. Payload of compressed message (permessage-deflate)
+// is decompressed if possible.
+func parseWebSocketMessages(direction string, data []byte, start int, writes []write, startedAt time.Time) []WebSocketMessage {
+	var (
+		messages   []WebSocketMessage
+		fragment   *WebSocketMessage
+		compressed bool
+	)
+
+	pos := start
+	for pos+2 <= len(data) {
+		fin := data[pos]&0x80 != 0
+		rsv1 := data[pos]&0x40 != 0
+		opcode := int(data[pos] & 0x0f)
+		masked := data[pos+1]&0x80 != 0
+		length := uint64(data[pos+1] & 0x7f)
+
+		p := pos + 2
+		switch length {
+		case 126:
+			if p+2 > len(data) {
+				return messages
+			}
+			length = uint64(binary.BigEndian.Uint16(data[p:]))
+			p += 2
+		case 127:
+			if p+8 > len(data) {
+				return messages
+			}
+			length = binary.BigEndian.Uint64(data[p:])
+			p += 8
+		}
+
+		var mask []byte
+		if masked {
+			if p+4 > len(data) {
+				return messages
+			}
+			mask = data[p : p+4]
+			p += 4
+		}
+
+		if length > uint64(len(data)-p) {
+			return messages
+		}
+		payload := make([]byte, int(length))
+		copy(payload, data[p:])
+		if mask != nil {
+			for i := range payload {
+				payload[i] ^= mask[i%4]
+			}
+		}
+		pos = p + int(length)
+
+		if opcode >= webSocketClose {
+			// Control frames can be injected in the middle of a fragmented message.
+			messages = append(messages, WebSocketMessage{
+				Direction: direction,
+				Opcode:    opcode,
+				Offset:    writeOffset(writes, pos, startedAt),
+				data:      payload,
+			})
+			continue
+		}
+
+		if opcode != webSocketContinuation {
+			fragment = &WebSocketMessage{Direction: direction, Opcode: opcode}
+			compressed = rsv1
+		}
+		if fragment == nil {
+			continue
+		}
+		fragment.data = append(fragment.data, payload...)
+
+		if fin {
+			if compressed {
+				fragment.data = inflate(fragment.data)
+			}
+			fragment.Offset = writeOffset(writes, pos, startedAt)
+			messages = append(messages, *fragment)
+			fragment = nil
+		}
+	}
+	return messages
+}
+
+// inflate decompresses payload compressed by permessage-deflate extension. If it fails (e.g., context
+// takeover is used), it returns the payload as it is.
+func inflate(payload []byte) []byte {
+	r := flate.NewReader(io.MultiReader(bytes.NewReader(payload), bytes.NewReader([]byte{0x00, 0x00, 0xff, 0xff})))
+	defer r.Close()
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return payload
+	}
+	return b
+}
+
+// webSocketPayload returns human readable payload of the message and sets its Type.
+func webSocketPayload(m *WebSocketMessage, message protoMessage) string {
+	m.Type = webSocketOpcodeNames[m.Opcode]
+	if m.Type == "" {
+		m.Type = fmt.Sprintf("opcode %d", m.Opcode)
+	}
+
+	switch m.Opcode {
+	case webSocketText:
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, m.data, "", "  "); err == nil {
+			return buf.String()
+		}
+		return string(m.data)
+	case webSocketBinary:
+		if message.valid() {
+			if s, _, err := message.decode(m.data); err == nil {
+				return s
+			}
+		}
+		return base64.StdEncoding.EncodeToString(m.data)
+	case webSocketClose:
+		if len(m.data) < 2 {
+			return ""
+		}
+		return fmt.Sprintf("%d %s", binary.BigEndian.Uint16(m.data), m.data[2:])
+	default:
+		return string(m.data)
+	}
+}
@@ -0,0 +1,291 @@
+package httpdoc
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+)
+
+var testWebSocketHandler = func(w http.ResponseWriter, r *http.Request) {
+	conn, rw, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer conn.Close()
+
+	accept := sha1.Sum([]byte(r.Header.Get("Sec-WebSocket-Key") + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+	fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n"+
+		"Sec-WebSocket-Protocol: chat\r\n\r\n", base64.StdEncoding.EncodeToString(accept[:]))
+	rw.Flush()
+
+	// Echo text messages until client closes the connection.
+	var message []byte
+	for {
+		b0, payload, err := readTestFrame(rw.Reader)
+		if err != nil {
+			return
+		}
+
+		switch b0 & 0x0f {
+		case webSocketPing:
+			writeTestFrame(rw, 0x80|webSocketPong, payload, false)
+		case webSocketClose:
+			writeTestFrame(rw, 0x80|webSocketClose, payload, false)
+			rw.Flush()
+			return
+		default:
+			message = append(message, payload...)
+			if b0&0x80 != 0 {
+				writeTestFrame(rw, 0x80|webSocketText, []byte(`{"echo":`+string(message)+`}`), false)
+				message = nil
+			}
+		}
+		rw.Flush()
+	}
+}
+
+// writeTestFrame writes a WebSocket frame. b0 is the first byte of the frame (FIN, RSV and opcode).
+func writeTestFrame(w io.Writer, b0 byte, payload []byte, masked bool) {
+	frame := []byte{b0}
+
+	var maskBit byte
+	if masked {
+		maskBit = 0x80
+	}
+	if len(payload) < 126 {
+		frame = append(frame, maskBit|byte(len(payload)))
+	} else {
+		frame = append(frame, maskBit|126, byte(len(payload)>>8), byte(len(payload)))
+	}
+
+	if masked {
+		mask := []byte{0x12, 0x34, 0x56, 0x78}
+		frame = append(frame, mask...)
+		for i, b := range payload {
+			frame = append(frame, b^mask[i%4])
+		}
+	} else {
+		frame = append(frame, payload...)
+	}
+	w.Write(frame)
+}
+
+// readTestFrame reads a WebSocket frame whose payload is shorter than 126 bytes.
+func readTestFrame(r io.Reader) (byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	var mask []byte
+	if header[1]&0x80 != 0 {
+		mask = make([]byte, 4)
+		if _, err := io.ReadFull(r, mask); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload := make([]byte, header[1]&0x7f)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		if mask != nil {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return header[0], payload, nil
+}
+
+func TestRecord_WebSocket(t *testing.T) {
+	doc := &Document{}
+	handler := Record(http.HandlerFunc(testWebSocketHandler), doc, &RecordOption{
+		ExcludeHeaders: testExcludeHeaders,
+	})
+
+	// Hijacked connections are not tracked by httptest.Server, so wait for Record by ourselves.
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+		close(done)
+	}))
+	defer ts.Close()
+
+	conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer conn.Close()
+
+	fmt.Fprintf(conn, "GET /v1/chat HTTP/1.1\r\n"+
+		"Host: %s\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"+
+		"Sec-WebSocket-Version: 13\r\n\r\n", ts.Listener.Addr())
+
+	br := bufio.NewReader(conn)
+	res, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("got %d, want %d", res.StatusCode, http.StatusSwitchingProtocols)
+	}
+
+	// Send fragmented text message with ping in the middle of it.
+	writeTestFrame(conn, webSocketText, []byte(`{"text":`), true)
+	writeTestFrame(conn, 0x80|webSocketPing, []byte("ping"), true)
+	if b0, _, err := readTestFrame(br); err != nil || b0&0x0f != webSocketPong {
+		t.Fatalf("expect pong: %x, %v", b0, err)
+	}
+	writeTestFrame(conn, 0x80|webSocketContinuation, []byte(`"hello"}`), true)
+	if _, _, err := readTestFrame(br); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	writeTestFrame(conn, 0x80|webSocketClose, []byte("\x03\xe8bye"), true)
+	if _, _, err := readTestFrame(br); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+
+	entry := doc.Entries[0]
+	if got, want := entry.ResponseStatusCode, http.StatusSwitchingProtocols; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+	if !containsData(entry.ResponseHeaders, "Sec-Websocket-Protocol") {
+		t.Fatalf("expect handshake response headers to be recorded: %v", entry.ResponseHeaders)
+	}
+	if entry.WebSocket == nil {
+		t.Fatal("expect WebSocket session to be recorded")
+	}
+	if got, want := entry.WebSocket.Subprotocol, "chat"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	want := []WebSocketMessage{
+		{Index: 0, Direction: WebSocketClientToServer, Opcode: webSocketPing, Type: "ping", Payload: "ping"},
+		{Index: 1, Direction: WebSocketServerToClient, Opcode: webSocketPong, Type: "pong", Payload: "ping"},
+		{Index: 2, Direction: WebSocketClientToServer, Opcode: webSocketText, Type: "text", Payload: "{\n  \"text\": \"hello\"\n}"},
+		{Index: 3, Direction: WebSocketServerToClient, Opcode: webSocketText, Type: "text", Payload: "{\n  \"echo\": {\n    \"text\": \"hello\"\n  }\n}"},
+		{Index: 4, Direction: WebSocketClientToServer, Opcode: webSocketClose, Type: "close", Payload: "1000 bye"},
+		{Index: 5, Direction: WebSocketServerToClient, Opcode: webSocketClose, Type: "close", Payload: "1000 bye"},
+	}
+	if got, want := len(entry.WebSocket.Messages), len(want); got != want {
+		t.Fatalf("got %d messages, want %d: %#v", got, want, entry.WebSocket.Messages)
+	}
+	for i, m := range entry.WebSocket.Messages {
+		if m.Offset <= 0 {
+			t.Fatalf("messages[%d]: unexpected offset %s", i, m.Offset)
+		}
+		m.Offset, m.data = 0, nil
+		if !reflect.DeepEqual(m, want[i]) {
+			t.Fatalf("messages[%d]: got %#v, want %#v", i, m, want[i])
+		}
+	}
+}
+
+func TestResponseWriter_Hijack_NotSupported(t *testing.T) {
+	rw := &responseWriter{ResponseWriter: httptest.NewRecorder()}
+	if _, _, err := rw.Hijack(); err == nil {
+		t.Fatal("expect to fail")
+	}
+}
+
+func TestParseWebSocketMessages(t *testing.T) {
+	var buf bytes.Buffer
+	writeTestFrame(&buf, 0x80|webSocketBinary, bytes.Repeat([]byte{0xff}, 200), false)
+	writeTestFrame(&buf, 0x80|webSocketText, []byte("truncated"), false)
+	data := buf.Bytes()[:buf.Len()-1]
+
+	got := parseWebSocketMessages(WebSocketServerToClient, data, 0, nil, time.Now())
+	if len(got) != 1 {
+		t.Fatalf("expect truncated frame to be ignored: %#v", got)
+	}
+	if got, want := len(got[0].data), 200; got != want {
+		t.Fatalf("got %d, want %d", got, want)
+	}
+}
+
+func TestWebSocketPayload(t *testing.T) {
+	body, err := proto.Marshal(&UserProtoResponse{Id: 7089, Name: "tcnksm"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := []struct {
+		message     WebSocketMessage
+		unmarshaler proto.Unmarshaler
+		want        string
+	}{
+		{WebSocketMessage{Opcode: webSocketText, data: []byte("hello")}, nil, "hello"},
+		{WebSocketMessage{Opcode: webSocketBinary, data: []byte{0xff, 0xfe}}, nil, "//4="},
+		{WebSocketMessage{Opcode: webSocketBinary, data: body}, &UserProtoResponse{}, "\"id\": 7089"},
+		{WebSocketMessage{Opcode: webSocketClose, data: []byte("\x03\xe9going away")}, nil, "1001 going away"},
+	}
+
+	for _, tc := range cases {
+		if got := webSocketPayload(&tc.message, protoMessage{unmarshaler: tc.unmarshaler}); !strings.Contains(got, tc.want) {
+			t.Fatalf("expect %q to contain %q", got, tc.want)
+		}
+	}
+}
+
+func containsData(data []Data, name string) bool {
+	for _, d := range data {
+		if d.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDocument_Generate_WebSocket(t *testing.T) {
+	doc := &Document{
+		Entries: []Entry{
+			{
+				Method:             "GET",
+				Path:               "/v1/chat",
+				ResponseStatusCode: http.StatusSwitchingProtocols,
+				WebSocket: &WebSocketSession{
+					Messages: []WebSocketMessage{
+						{Index: 0, Direction: WebSocketClientToServer, Type: "text", Payload: "hello", Offset: time.Millisecond},
+					},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := doc.generate(&buf); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if want := "**#0 client->server** (text)\n\n```javascript\nhello\n```"; !strings.Contains(buf.String(), want) {
+		t.Fatalf("expect %q to contain %q", buf.String(), want)
+	}
+	if strings.Contains(buf.String(), "curl") {
+		t.Fatalf("expect curl snippet not to be rendered for WebSocket: %q", buf.String())
+	}
+}